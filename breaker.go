@@ -0,0 +1,308 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// Calls are made. Failures are counted.
+	CIRCUIT_CLOSED CircuitState = iota
+	// Calls fail fast with a CircuitOpenError until the cool-down has passed.
+	CIRCUIT_OPEN
+	// The API is probed with a status check. If it is up a single trial call is made, which decides if the circuit closes or opens again.
+	CIRCUIT_HALF_OPEN
+)
+
+// Returns state as string.
+func (s CircuitState) String() string {
+	switch s {
+	case CIRCUIT_CLOSED:
+		return "closed"
+	case CIRCUIT_OPEN:
+		return "open"
+	case CIRCUIT_HALF_OPEN:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// Sentinel error for classifying a CircuitOpenError with errors.Is.
+var ErrCircuitOpen = errors.New("Circuit open")
+
+// CircuitOpenError is returned for calls rejected by an open circuit.
+type CircuitOpenError struct {
+	// Endpoint group of the circuit.
+	Group string
+	// Earliest time the circuit is probed again.
+	Until time.Time
+}
+
+// Error method to fulfil the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(`Circuit open for endpoint group "%s". Calls are made again after: "%s"`, e.Group, e.Until.Format(time.RFC3339))
+}
+
+// Is method for matching ErrCircuitOpen with errors.Is.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitSettings configures the circuits of an endpoint group.
+type CircuitSettings struct {
+	// Number of consecutive failed calls opening the circuit. Defaults to 5.
+	FailureThreshold int
+	// Time the circuit stays open before the API is probed. Defaults to 30 seconds.
+	CoolDown time.Duration
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// Settings of endpoint groups without settings of their own.
+	CircuitSettings
+	// Settings per endpoint group, e.g. "files".
+	Groups map[string]CircuitSettings
+	// Maps endpoints to endpoint groups. EndpointGroup is used if not set.
+	GroupOf func(endpoint string) string
+	// Decides if the outcome of a call is a failure. IsCircuitFailure is used if not set.
+	IsFailure func(resp *http.Response, err error) bool
+	// Called when a circuit changes state, e.g. for alerting. Called after the change, without any locks held.
+	OnStateChange func(group string, from CircuitState, to CircuitState)
+}
+
+// CircuitBreaker stops an APIClient from making calls against endpoints that keep failing.
+// Each endpoint group has its own circuit. A circuit opens after a number of consecutive failures,
+// after which calls fail fast with a CircuitOpenError. Once the cool-down has passed the next call
+// probes the API with a status check and, if it is up, is made as a trial deciding if the circuit closes.
+// Share a single CircuitBreaker between clients to let them share the circuits.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   CircuitBreakerConfig
+	circuits map[string]*circuit
+}
+
+// State of the circuit of an endpoint group.
+type circuit struct {
+	state    CircuitState
+	failures int
+	until    time.Time
+	// Set while a probe and trial call is in progress in half-open state.
+	probing bool
+}
+
+// Creates new CircuitBreaker.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	config.CircuitSettings = config.CircuitSettings.withDefaults()
+	return &CircuitBreaker{
+		config:   config,
+		circuits: map[string]*circuit{},
+	}
+}
+
+// Returns settings with defaults applied.
+func (s CircuitSettings) withDefaults() CircuitSettings {
+	if s.FailureThreshold < 1 {
+		s.FailureThreshold = 5
+	}
+	if s.CoolDown <= 0 {
+		s.CoolDown = 30 * time.Second
+	}
+	return s
+}
+
+// EndpointGroup returns the group of endpoint, which is its first path segment, e.g. "print_orders" for "print_orders/4".
+func EndpointGroup(endpoint string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+	return group
+}
+
+// IsCircuitFailure checks if the outcome of a call signals that the API is degraded.
+// Errors other than cancellation and server errors are failures.
+func IsCircuitFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+// Returns the state of the circuit of an endpoint group.
+func (b *CircuitBreaker) State(group string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cb, ok := b.circuits[group]; ok {
+		return cb.state
+	}
+	return CIRCUIT_CLOSED
+}
+
+// Closes all circuits.
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	var changes []func()
+	for group, cb := range b.circuits {
+		if cb.state != CIRCUIT_CLOSED {
+			changes = append(changes, b.stateChange(group, cb.state, CIRCUIT_CLOSED))
+		}
+	}
+	b.circuits = map[string]*circuit{}
+	b.mu.Unlock()
+
+	for _, v := range changes {
+		v()
+	}
+}
+
+// Returns the settings of an endpoint group.
+func (b *CircuitBreaker) settings(group string) CircuitSettings {
+	if s, ok := b.config.Groups[group]; ok {
+		return s.withDefaults()
+	}
+	return b.config.CircuitSettings
+}
+
+// Returns the group of endpoint.
+func (b *CircuitBreaker) groupOf(endpoint string) string {
+	if b.config.GroupOf != nil {
+		return b.config.GroupOf(endpoint)
+	}
+	return EndpointGroup(endpoint)
+}
+
+// Checks if a call to endpoint may be made, probing the API through probe if the circuit is due to be half-opened.
+// The returned done function must be called with the outcome of the call once it completes.
+func (b *CircuitBreaker) allow(ctx context.Context, endpoint string, probe func(ctx context.Context) bool) (func(resp *http.Response, err error), error) {
+	group := b.groupOf(endpoint)
+
+	b.mu.Lock()
+	cb, ok := b.circuits[group]
+	if !ok {
+		cb = &circuit{}
+		b.circuits[group] = cb
+	}
+
+	switch cb.state {
+	case CIRCUIT_CLOSED:
+		b.mu.Unlock()
+		return b.done(group, false), nil
+	case CIRCUIT_OPEN:
+		if time.Now().Before(cb.until) {
+			err := &CircuitOpenError{Group: group, Until: cb.until}
+			b.mu.Unlock()
+			return nil, err
+		}
+	case CIRCUIT_HALF_OPEN:
+		if cb.probing {
+			err := &CircuitOpenError{Group: group, Until: cb.until}
+			b.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	// This call probes the API and is made as the trial.
+	var change func()
+	if cb.state == CIRCUIT_OPEN {
+		change = b.stateChange(group, CIRCUIT_OPEN, CIRCUIT_HALF_OPEN)
+		cb.state = CIRCUIT_HALF_OPEN
+	}
+	cb.probing = true
+	b.mu.Unlock()
+
+	if change != nil {
+		change()
+	}
+
+	if !probe(ctx) {
+		// A cancelled probe does not tell anything about the API.
+		if err := ctx.Err(); errors.Is(err, context.Canceled) {
+			b.record(group, true, false, true)
+			return nil, err
+		}
+
+		b.record(group, true, true, false)
+
+		b.mu.Lock()
+		err := &CircuitOpenError{Group: group, Until: cb.until}
+		b.mu.Unlock()
+		return nil, err
+	}
+
+	return b.done(group, true), nil
+}
+
+// Returns a function recording the outcome of a call in the circuit of group. Set trial for the trial call of a half-open circuit.
+func (b *CircuitBreaker) done(group string, trial bool) func(resp *http.Response, err error) {
+	return func(resp *http.Response, err error) {
+		failed := IsCircuitFailure(resp, err)
+		if b.config.IsFailure != nil {
+			failed = b.config.IsFailure(resp, err)
+		}
+		b.record(group, trial, failed, errors.Is(err, context.Canceled))
+	}
+}
+
+// Records the outcome of a call in the circuit of group. Ignored outcomes, e.g. of cancelled calls, are neither failures nor successes.
+func (b *CircuitBreaker) record(group string, trial bool, failed bool, ignored bool) {
+	b.mu.Lock()
+	cb := b.circuits[group]
+	if cb == nil {
+		// The breaker was reset during the call.
+		b.mu.Unlock()
+		return
+	}
+
+	to := cb.state
+	switch {
+	case trial && cb.state != CIRCUIT_HALF_OPEN:
+		// The breaker was reset during the trial.
+	case trial && ignored:
+		cb.probing = false
+	case trial && failed:
+		to = CIRCUIT_OPEN
+	case trial:
+		to = CIRCUIT_CLOSED
+	case cb.state != CIRCUIT_CLOSED || ignored:
+	case failed:
+		cb.failures++
+		if cb.failures >= b.settings(group).FailureThreshold {
+			to = CIRCUIT_OPEN
+		}
+	default:
+		cb.failures = 0
+	}
+
+	var change func()
+	if to != cb.state {
+		change = b.stateChange(group, cb.state, to)
+		cb.state = to
+		cb.failures = 0
+		cb.probing = false
+		if to == CIRCUIT_OPEN {
+			cb.until = time.Now().Add(b.settings(group).CoolDown)
+		}
+	}
+	b.mu.Unlock()
+
+	if change != nil {
+		change()
+	}
+}
+
+// Returns a function calling OnStateChange, to be called once the lock is released.
+func (b *CircuitBreaker) stateChange(group string, from CircuitState, to CircuitState) func() {
+	return func() {
+		if b.config.OnStateChange != nil {
+			b.config.OnStateChange(group, from, to)
+		}
+	}
+}
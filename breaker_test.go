@@ -0,0 +1,218 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitOpensAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+	caller := &DegradableAPICaller{Status: http.StatusInternalServerError}
+	var changes []string
+	b := NewCircuitBreaker(CircuitBreakerConfig{
+		CircuitSettings: CircuitSettings{FailureThreshold: 2, CoolDown: time.Hour},
+		OnStateChange: func(group string, from CircuitState, to CircuitState) {
+			changes = append(changes, fmt.Sprintf("%s:%s->%s", group, from, to))
+		},
+	})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Breaker: b}
+
+	for n := 0; n < 2; n++ {
+		if err := c.Get(GroupEndpoint("print_orders/1"), &struct{}{}); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected call %d to be made but circuit was open.", n+1)
+		}
+	}
+
+	err := c.Get(GroupEndpoint("print_orders/2"), &struct{}{})
+	var open *CircuitOpenError
+	if !errors.As(err, &open) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf(`Expected CircuitOpenError but got: "%v"`, err)
+	}
+	if open.Group != "print_orders" {
+		t.Errorf(`Expected open circuit of group "print_orders" but got: "%s"`, open.Group)
+	}
+	if n := caller.Calls("print_orders"); n != 2 {
+		t.Errorf("Expected 2 calls to be made but got %d.", n)
+	}
+
+	if b.State("print_orders") != CIRCUIT_OPEN {
+		t.Errorf(`Expected circuit to be open but got: "%s"`, b.State("print_orders"))
+	}
+	if len(changes) != 1 || changes[0] != "print_orders:closed->open" {
+		t.Errorf("Expected state change to be reported but got: %v", changes)
+	}
+
+	// Other endpoint groups are not affected.
+	caller.SetStatus(http.StatusOK)
+	if err := c.Get(GroupEndpoint("countries"), &struct{}{}); err != nil {
+		t.Errorf(`Expected call to other endpoint group to succeed but got: "%v"`, err)
+	}
+}
+
+func TestCircuitSuccessResetsFailures(t *testing.T) {
+	t.Parallel()
+	caller := &DegradableAPICaller{Status: http.StatusInternalServerError}
+	b := NewCircuitBreaker(CircuitBreakerConfig{CircuitSettings: CircuitSettings{FailureThreshold: 2}})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Breaker: b}
+
+	c.Get(GroupEndpoint("files"), &struct{}{})
+	caller.SetStatus(http.StatusOK)
+	c.Get(GroupEndpoint("files"), &struct{}{})
+	caller.SetStatus(http.StatusInternalServerError)
+	c.Get(GroupEndpoint("files"), &struct{}{})
+
+	if b.State("files") != CIRCUIT_CLOSED {
+		t.Errorf(`Expected circuit to be closed but got: "%s"`, b.State("files"))
+	}
+}
+
+func TestCircuitThresholdsPerEndpointGroup(t *testing.T) {
+	t.Parallel()
+	caller := &DegradableAPICaller{Status: http.StatusBadGateway}
+	b := NewCircuitBreaker(CircuitBreakerConfig{
+		CircuitSettings: CircuitSettings{FailureThreshold: 3},
+		Groups:          map[string]CircuitSettings{"files": {FailureThreshold: 1}},
+	})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Breaker: b}
+
+	c.Get(GroupEndpoint("files/1"), &struct{}{})
+	c.Get(GroupEndpoint("print_orders/1"), &struct{}{})
+
+	if b.State("files") != CIRCUIT_OPEN {
+		t.Errorf(`Expected files circuit to be open but got: "%s"`, b.State("files"))
+	}
+	if b.State("print_orders") != CIRCUIT_CLOSED {
+		t.Errorf(`Expected print_orders circuit to be closed but got: "%s"`, b.State("print_orders"))
+	}
+}
+
+func TestHalfOpenCircuitProbesStatusCheckAndCloses(t *testing.T) {
+	t.Parallel()
+	caller := &DegradableAPICaller{Status: http.StatusServiceUnavailable}
+	var mu sync.Mutex
+	var changes []string
+	b := NewCircuitBreaker(CircuitBreakerConfig{
+		CircuitSettings: CircuitSettings{FailureThreshold: 1, CoolDown: 10 * time.Millisecond},
+		OnStateChange: func(group string, from CircuitState, to CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+		},
+	})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Breaker: b}
+
+	c.Get(GroupEndpoint("print_orders"), &struct{}{})
+	caller.SetStatus(http.StatusOK)
+	time.Sleep(20 * time.Millisecond)
+
+	if err := c.Get(GroupEndpoint("print_orders"), &struct{}{}); err != nil {
+		t.Fatalf(`Expected trial call to succeed but got: "%v"`, err)
+	}
+
+	if n := caller.Calls(RESOURCE_STATUSCHECK); n != 1 {
+		t.Errorf("Expected the API to be probed once with a status check but got %d.", n)
+	}
+	if b.State("print_orders") != CIRCUIT_CLOSED {
+		t.Errorf(`Expected circuit to be closed but got: "%s"`, b.State("print_orders"))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(changes, ",") != "closed->open,open->half-open,half-open->closed" {
+		t.Errorf("Expected state changes to be reported but got: %v", changes)
+	}
+}
+
+func TestHalfOpenCircuitReopensWhenStatusCheckFails(t *testing.T) {
+	t.Parallel()
+	caller := &DegradableAPICaller{Status: http.StatusServiceUnavailable}
+	b := NewCircuitBreaker(CircuitBreakerConfig{CircuitSettings: CircuitSettings{FailureThreshold: 1, CoolDown: 10 * time.Millisecond}})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Breaker: b}
+
+	c.Get(GroupEndpoint("print_orders"), &struct{}{})
+	time.Sleep(20 * time.Millisecond)
+
+	if err := c.Get(GroupEndpoint("print_orders"), &struct{}{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf(`Expected circuit to fail fast after failed probe but got: "%v"`, err)
+	}
+
+	if n := caller.Calls("print_orders"); n != 1 {
+		t.Errorf("Expected no trial call to be made but got %d calls.", n)
+	}
+	if b.State("print_orders") != CIRCUIT_OPEN {
+		t.Errorf(`Expected circuit to be open again but got: "%s"`, b.State("print_orders"))
+	}
+}
+
+func TestEndpointGroup(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"print_orders":            "print_orders",
+		"print_orders/4/statuses": "print_orders",
+		"/files/1":                "files",
+	}
+
+	for endpoint, expected := range tests {
+		if g := EndpointGroup(endpoint); g != expected {
+			t.Errorf(`Expected group "%s" of "%s" but got: "%s"`, expected, endpoint, g)
+		}
+	}
+}
+
+// Endpoint with a configurable path.
+type GroupEndpoint string
+
+// For fulfilling the endpointer interface.
+func (e GroupEndpoint) GetEndpoint() string {
+	return string(e)
+}
+
+// APICaller mock responding with a configurable status and counting calls per endpoint group.
+type DegradableAPICaller struct {
+	mu     sync.Mutex
+	Status int
+	calls  map[string]int
+}
+
+func (c *DegradableAPICaller) Call(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.calls == nil {
+		c.calls = map[string]int{}
+	}
+
+	group := RESOURCE_STATUSCHECK
+	if !strings.HasSuffix(r.URL.Path, RESOURCE_STATUSCHECK) {
+		group = EndpointGroup(strings.TrimPrefix(r.URL.Path, "somebaseurl/"+API+"/"+API_VERSION+"/"))
+	}
+	c.calls[group]++
+
+	return createCallerResponse(c.Status, `{}`), nil
+}
+
+func (c *DegradableAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *DegradableAPICaller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
+
+func (c *DegradableAPICaller) SetStatus(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Status = status
+}
+
+func (c *DegradableAPICaller) Calls(group string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[group]
+}
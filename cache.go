@@ -0,0 +1,312 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TTL of endpoint groups whose responses are not cached.
+const CACHE_NO_STORE time.Duration = -1
+
+// Cache caches the responses of GET calls made by an APIClient, keyed by URL including the query.
+//
+// Cached responses are served without calling the API until their TTL has passed. Then they are revalidated
+// with a conditional request using their ETag or Last-Modified, and served again if the API responds with
+// 304 Not Modified. The Cache-Control header of responses is honored: no-store responses are not cached,
+// no-cache responses are always revalidated and max-age caps the TTL.
+//
+// Responses are not invalidated by POST, PUT or DELETE calls, so only cache resources that change rarely
+// or where a stale response within the TTL is acceptable.
+type Cache struct {
+	Store CacheStore
+	// TTL per endpoint group, e.g. "countries". Use CACHE_NO_STORE for groups that should not be cached,
+	// and zero for groups that should always be revalidated.
+	TTLs map[string]time.Duration
+	// TTL of endpoint groups not in TTLs.
+	DefaultTTL time.Duration
+}
+
+// Returns Cache with TTLs suitable for the Publit production API: countries are cached for a day and print data
+// for an hour. Print orders, statuses and delivery numbers change as orders are processed, and files carry
+// presigned URLs that must not be stored, so they are not cached.
+func DefaultCache(store CacheStore) *Cache {
+	return &Cache{
+		Store: store,
+		TTLs: map[string]time.Duration{
+			"countries":              24 * time.Hour,
+			"print_order_print_data": time.Hour,
+		},
+		DefaultTTL: CACHE_NO_STORE,
+	}
+}
+
+// CacheEntry is a cached response.
+type CacheEntry struct {
+	// Body of the 200 OK response.
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	// Time after which the entry is revalidated.
+	Expires time.Time `json:"expires"`
+}
+
+// CacheStore stores cache entries. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, e *CacheEntry) error
+	Delete(key string) error
+}
+
+// Returns the TTL of endpoint.
+func (c *Cache) ttl(endpoint string) time.Duration {
+	if ttl, ok := c.TTLs[EndpointGroup(endpoint)]; ok {
+		return ttl
+	}
+	return c.DefaultTTL
+}
+
+// Performs GET request to endpoint through the cache, decoding the response into model.
+func (c APIClient) cachedGet(endpoint string, req *http.Request, model interface{}) error {
+	key := req.URL.String()
+	entry, cached := c.Cache.Store.Get(key)
+
+	if cached && time.Now().Before(entry.Expires) {
+		return json.Unmarshal(entry.Body, model)
+	}
+
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := c.call(endpoint, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	ttl, store := cacheTTL(c.Cache.ttl(endpoint), resp.Header)
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		e := *entry
+		e.Expires = time.Now().Add(ttl)
+		if store {
+			c.Cache.Store.Set(key, &e)
+		}
+		return json.Unmarshal(e.Body, model)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return MakeResponseError(resp)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, model); err != nil {
+		return err
+	}
+
+	if store {
+		now := time.Now()
+		c.Cache.Store.Set(key, &CacheEntry{
+			Body:         b,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StoredAt:     now,
+			Expires:      now.Add(ttl),
+		})
+	}
+
+	return nil
+}
+
+// Returns the TTL of a response given the configured ttl and the Cache-Control header, and whether it may be stored.
+func cacheTTL(ttl time.Duration, h http.Header) (time.Duration, bool) {
+	for _, v := range strings.Split(h.Get("Cache-Control"), ",") {
+		directive, value, _ := strings.Cut(strings.ToLower(strings.TrimSpace(v)), "=")
+		switch directive {
+		case "no-store":
+			return 0, false
+		case "no-cache":
+			ttl = 0
+		case "max-age":
+			if s, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && time.Duration(s)*time.Second < ttl {
+				ttl = time.Duration(s) * time.Second
+			}
+		}
+	}
+	return ttl, true
+}
+
+// Checks if status code is that of a successful response. 304 Not Modified is a successful revalidation of a cached response.
+func successStatus(code int) bool {
+	return code == http.StatusOK || code == http.StatusNotModified
+}
+
+// MemoryCache is an in-memory CacheStore evicting the least recently used entries.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+// Item of the LRU list of a MemoryCache.
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// Creates new MemoryCache holding at most maxEntries entries. Zero means no limit.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// Get method to fulfil the CacheStore interface.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return el.Value.(*memoryCacheItem).entry, true
+}
+
+// Set method to fulfil the CacheStore interface.
+func (m *MemoryCache) Set(key string, e *CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		el.Value.(*memoryCacheItem).entry = e
+		m.lru.MoveToFront(el)
+		return nil
+	}
+
+	m.entries[key] = m.lru.PushFront(&memoryCacheItem{key: key, entry: e})
+
+	if m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+	return nil
+}
+
+// Delete method to fulfil the CacheStore interface.
+func (m *MemoryCache) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.lru.Remove(el)
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Returns the number of entries in the cache.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+// DiskCache is a CacheStore keeping each entry in a JSON file in a directory, surviving restarts.
+// Entries are never evicted, remove the directory to clear the cache.
+type DiskCache struct {
+	Dir string
+}
+
+// Creates new DiskCache storing entries in dir, which is created if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+// Get method to fulfil the CacheStore interface. Unreadable entries are treated as missing.
+func (d *DiskCache) Get(key string) (*CacheEntry, bool) {
+	b, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	e := &CacheEntry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, false
+	}
+	return e, true
+}
+
+// Set method to fulfil the CacheStore interface.
+func (d *DiskCache) Set(key string, e *CacheEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so that concurrent readers never see a partial entry.
+	f, err := os.CreateTemp(d.Dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), d.path(key))
+}
+
+// Delete method to fulfil the CacheStore interface.
+func (d *DiskCache) Delete(key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Returns the path of the file of key.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:])+".json")
+}
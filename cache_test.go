@@ -0,0 +1,220 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachedResponsesAreServedWithoutCalls(t *testing.T) {
+	t.Parallel()
+	caller := &CachingAPICaller{ETag: `"v1"`}
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: &Cache{Store: NewMemoryCache(0), DefaultTTL: time.Hour}}
+
+	for n := 0; n < 3; n++ {
+		m := &CachedModel{}
+		if err := c.Get(GroupEndpoint("countries"), m); err != nil {
+			t.Fatal("Got error but was not expecting one.", err)
+		}
+		if m.Name != "Sweden" {
+			t.Errorf(`Expected cached model to be decoded but got: "%s"`, m.Name)
+		}
+	}
+
+	if n := caller.Calls(); n != 1 {
+		t.Errorf("Expected 1 call but got %d.", n)
+	}
+}
+
+func TestCachedResponsesAreKeyedByQuery(t *testing.T) {
+	t.Parallel()
+	caller := &CachingAPICaller{}
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: &Cache{Store: NewMemoryCache(0), DefaultTTL: time.Hour}}
+
+	c.Get(GroupEndpoint("countries"), &CachedModel{}, func(q url.Values) { q.Set("limit", "1") })
+	c.Get(GroupEndpoint("countries"), &CachedModel{}, func(q url.Values) { q.Set("limit", "2") })
+
+	if n := caller.Calls(); n != 2 {
+		t.Errorf("Expected 2 calls but got %d.", n)
+	}
+}
+
+func TestExpiredResponsesAreRevalidated(t *testing.T) {
+	t.Parallel()
+	tests := map[string]*CachingAPICaller{
+		"ETag":          {ETag: `"v1"`},
+		"Last-Modified": {LastModified: "Mon, 02 Jan 2017 15:04:05 GMT"},
+	}
+
+	for name, caller := range tests {
+		c := APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: &Cache{Store: NewMemoryCache(0)}}
+
+		c.Get(GroupEndpoint("countries"), &CachedModel{})
+		m := &CachedModel{}
+		if err := c.Get(GroupEndpoint("countries"), m); err != nil {
+			t.Fatal("Got error but was not expecting one.", err)
+		}
+
+		if m.Name != "Sweden" {
+			t.Errorf(`%s: Expected revalidated model to be decoded but got: "%s"`, name, m.Name)
+		}
+		if caller.Calls() != 2 || caller.NotModified() != 1 {
+			t.Errorf("%s: Expected 2 calls of which 1 was not modified but got %d and %d.", name, caller.Calls(), caller.NotModified())
+		}
+	}
+}
+
+func TestCacheControlIsHonored(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int{
+		"no-store":  0,
+		"no-cache":  1,
+		"max-age=0": 1,
+		"max-age=60": 0,
+	}
+
+	for cc, notModified := range tests {
+		caller := &CachingAPICaller{ETag: `"v1"`, CacheControl: cc}
+		c := APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: &Cache{Store: NewMemoryCache(0), DefaultTTL: time.Hour}}
+
+		c.Get(GroupEndpoint("countries"), &CachedModel{})
+		c.Get(GroupEndpoint("countries"), &CachedModel{})
+
+		if caller.NotModified() != notModified {
+			t.Errorf(`%s: Expected %d not modified responses but got %d.`, cc, notModified, caller.NotModified())
+		}
+	}
+}
+
+func TestDefaultCacheDoesNotCachePrintOrders(t *testing.T) {
+	t.Parallel()
+	caller := &CachingAPICaller{ETag: `"v1"`}
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: DefaultCache(NewMemoryCache(0))}
+
+	c.Get(GroupEndpoint("print_orders/1"), &CachedModel{})
+	c.Get(GroupEndpoint("print_orders/1"), &CachedModel{})
+	c.Get(GroupEndpoint("countries"), &CachedModel{})
+	c.Get(GroupEndpoint("countries"), &CachedModel{})
+
+	if caller.Calls() != 3 || caller.NotModified() != 0 {
+		t.Errorf("Expected print orders to be fetched every time and countries once but got %d calls.", caller.Calls())
+	}
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	m := NewMemoryCache(2)
+
+	m.Set("a", &CacheEntry{})
+	m.Set("b", &CacheEntry{})
+	m.Get("a")
+	m.Set("c", &CacheEntry{})
+
+	if _, ok := m.Get("b"); ok {
+		t.Error("Expected least recently used entry to be evicted.")
+	}
+	if _, ok := m.Get("a"); !ok {
+		t.Error("Expected recently used entry to be kept.")
+	}
+	if m.Len() != 2 {
+		t.Errorf("Expected 2 entries but got %d.", m.Len())
+	}
+}
+
+func TestDiskCacheKeepsEntries(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	if err := d.Set("somebaseurl/countries", &CacheEntry{Body: []byte(`{}`), ETag: `"v1"`}); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	// A new store on the same directory, e.g. after a restart.
+	d, _ = NewDiskCache(dir)
+	e, ok := d.Get("somebaseurl/countries")
+	if !ok || e.ETag != `"v1"` || string(e.Body) != `{}` {
+		t.Errorf("Expected stored entry but got: %+v", e)
+	}
+
+	d.Delete("somebaseurl/countries")
+	if _, ok := d.Get("somebaseurl/countries"); ok {
+		t.Error("Expected entry to be deleted.")
+	}
+}
+
+// Model of cached responses.
+type CachedModel struct {
+	Name string `json:"name"`
+}
+
+// APICaller mock supporting conditional requests.
+type CachingAPICaller struct {
+	ETag         string
+	LastModified string
+	CacheControl string
+
+	mu          sync.Mutex
+	calls       int
+	notModified int
+}
+
+func (c *CachingAPICaller) Call(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+
+	resp := createCallerResponse(http.StatusOK, `{"name":"Sweden"}`)
+	resp.Header = http.Header{}
+	if c.CacheControl != "" {
+		resp.Header.Set("Cache-Control", c.CacheControl)
+	}
+
+	if (c.ETag != "" && r.Header.Get("If-None-Match") == c.ETag) ||
+		(c.LastModified != "" && r.Header.Get("If-Modified-Since") == c.LastModified) {
+		c.notModified++
+		resp.StatusCode = http.StatusNotModified
+		resp.Body = nil
+		return resp, nil
+	}
+
+	if c.ETag != "" {
+		resp.Header.Set("ETag", c.ETag)
+	}
+	if c.LastModified != "" {
+		resp.Header.Set("Last-Modified", c.LastModified)
+	}
+	return resp, nil
+}
+
+func (c *CachingAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *CachingAPICaller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
+
+func (c *CachingAPICaller) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *CachingAPICaller) NotModified() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.notModified
+}
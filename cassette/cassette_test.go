@@ -0,0 +1,201 @@
+package cassette
+
+import (
+	"context"
+	"errors"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/file"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorder"
+	"github.com/publitsweden/ProductionAPIGoSDK/productiontest"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeQuerySortsAndDropsVolatileParams(t *testing.T) {
+	t.Parallel()
+	q := NormalizeQuery("with=statuses&X-Amz-Expires=900&limit=10&X-Amz-Signature=abc")
+
+	if q != "limit=10&with=statuses" {
+		t.Errorf(`Expected normalized query "limit=10&with=statuses" but got: "%s"`, q)
+	}
+}
+
+func TestCanRecordAndReplayCalls(t *testing.T) {
+	s := productiontest.NewServer()
+	s.AddPrintOrders(&printorder.PrintOrder{ClientRef: "ref", RecipientFirstname: "Jane", RecipientLastname: "Doe"})
+	content := []byte{0x25, 0x50, 0x44, 0x46, 0xff, 0xfe, 0x00}
+	id := s.AddFile(&file.File{OriginalName: "book.pdf"}, content)
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	defaultGetter, defaultContextGetter := file.PlainGetter, file.PlainContextGetter
+	defer func() { file.PlainGetter, file.PlainContextGetter = defaultGetter, defaultContextGetter }()
+
+	// Record.
+	rec := NewRecorder(s.Client().Client, path)
+	c := production.APIClient{Client: rec, BaseUrl: s.URL}
+	file.PlainGetter = rec.PlainGetter(s.Server.Client().Get)
+	file.PlainContextGetter = rec.PlainContextGetter(func(ctx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return s.Server.Client().Do(req)
+	})
+
+	recorded := exercise(t, c, id, filepath.Join(dir, "recorded"))
+	s.Close()
+
+	if err := rec.Save(); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"Jane", "Doe"} {
+		if strings.Contains(string(b), v) {
+			t.Errorf(`Expected "%s" to be scrubbed from cassette but got: %s`, v, b)
+		}
+	}
+	if !strings.Contains(string(b), "X-Amz-Signature="+production.REDACTED) {
+		t.Errorf("Expected presigned URL signature to be scrubbed from cassette but got: %s", b)
+	}
+
+	// Replay, with the server closed.
+	rep, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	c = production.APIClient{Client: rep, BaseUrl: s.URL}
+	file.PlainGetter = rep.PlainGetter()
+	file.PlainContextGetter = rep.PlainContextGetter()
+
+	replayed := exercise(t, c, id, filepath.Join(dir, "replayed"))
+
+	if replayed != recorded {
+		t.Errorf(`Expected replayed client reference "%s" but got: "%s"`, recorded, replayed)
+	}
+
+	d, err := ioutil.ReadFile(filepath.Join(dir, "replayed", "book.pdf"))
+	if err != nil || string(d) != string(content) {
+		t.Errorf(`Expected replayed download "%v" but got: "%v", "%v"`, content, d, err)
+	}
+
+	if err := rep.Err(); err != nil {
+		t.Errorf("Expected all requests to match but got: %v", err)
+	}
+	if l := rep.Unused(); len(l) != 0 {
+		t.Errorf("Expected all interactions to be replayed but %d were not.", len(l))
+	}
+}
+
+func TestPersonalDataIsScrubbedFromRecordedQueries(t *testing.T) {
+	t.Parallel()
+	s := productiontest.NewServer()
+	defer s.Close()
+	s.AddPrintOrders(&printorder.PrintOrder{RecipientFirstname: "Jane", DeliveryCity: "Stockholm"})
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	filter := func(c production.APIClient) (*printorder.IndexResponse, error) {
+		return printorder.Index(c,
+			common.QueryAttr(common.AttrQuery{Name: printorder.RECIPIENT_FIRSTNAME, Value: "Jane"}),
+			common.QueryAttr(common.AttrQuery{Name: printorder.DELIVERY_CITY, Value: "Stockholm"}),
+		)
+	}
+
+	rec := NewRecorder(s.Client().Client, path)
+	if _, err := filter(production.APIClient{Client: rec, BaseUrl: s.URL}); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"Jane", "Stockholm"} {
+		if strings.Contains(string(b), v) {
+			t.Errorf(`Expected "%s" to be scrubbed from cassette but got: %s`, v, b)
+		}
+	}
+
+	rep, err := NewReplayer(path)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	if _, err := filter(production.APIClient{Client: rep, BaseUrl: s.URL}); err != nil {
+		t.Error("Expected filtered request to match its scrubbed recording but got error.", err)
+	}
+}
+
+func TestReplayerFailsUnmatchedRequests(t *testing.T) {
+	t.Parallel()
+	rep := NewCassetteReplayer(&Cassette{Interactions: []*Interaction{
+		{
+			Request:  Request{Method: "GET", Path: "/production/v2.0/print_orders", Query: "limit=1"},
+			Response: Response{StatusCode: 200, Body: `{"data":[]}`},
+		},
+	}})
+	c := production.APIClient{Client: rep, BaseUrl: "https://url.to.publit"}
+
+	_, err := printorder.Index(c, common.QueryLimit(2, 0))
+
+	var unmatched *UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf(`Expected UnmatchedError but got: "%v"`, err)
+	}
+	if unmatched.Method != "GET" || unmatched.Path != "/production/v2.0/print_orders" {
+		t.Errorf(`Expected unmatched request to be named but got: "%v"`, unmatched)
+	}
+
+	if rep.Err() == nil {
+		t.Error("Expected unmatched request to be reported.")
+	}
+	if len(rep.Unused()) != 1 {
+		t.Error("Expected recorded interaction to be unused.")
+	}
+}
+
+// Shows print order and downloads file, with and without context. Returns the client reference of the print order.
+func exercise(t *testing.T, c production.APIClient, fileID int, outDir string) string {
+	po, err := printorder.Show(c, 1)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if err := os.Mkdir(outDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := file.FileList{&file.File{ID: fileID, OriginalName: "book.pdf"}}.DownloadFiles(c, outDir)
+	if err != nil || errs[fileID] != nil {
+		t.Fatalf(`Expected download to succeed but got: "%v", "%v"`, err, errs[fileID])
+	}
+
+	errs, err = file.FileList{&file.File{ID: fileID, OriginalName: "book.pdf"}}.DownloadFilesContext(context.Background(), production.GetterWithContext(c), outDir)
+	if err != nil || errs[fileID] != nil {
+		t.Fatalf(`Expected download with context to succeed but got: "%v", "%v"`, err, errs[fileID])
+	}
+
+	return po.ClientRef
+}
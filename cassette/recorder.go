@@ -0,0 +1,166 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Response headers kept in recordings. Other headers, e.g. cookies, are not recorded.
+var recordedHeaders []string = []string{"Content-Type", "Date", "Etag", "Last-Modified", "Cache-Control", "Retry-After"}
+
+// Recorder is an APICaller recording the interactions of another APICaller.
+// Call Save to write the recorded interactions to the cassette file.
+type Recorder struct {
+	// Wrapped APICaller making the actual calls.
+	Caller production.APICaller
+	// Path of the cassette file.
+	Path string
+	// Scrubs recorded bodies. Defaults to production.Redact.
+	Scrub func(s string) string
+	// Scrubs recorded queries. Defaults to production.RedactQuery.
+	ScrubQuery func(query string) string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// Creates new Recorder recording the interactions of c to the cassette file at path.
+func NewRecorder(c production.APICaller, path string) *Recorder {
+	return &Recorder{
+		Caller:     c,
+		Path:       path,
+		Scrub:      production.Redact,
+		ScrubQuery: production.RedactQuery,
+	}
+}
+
+// Call method to fulfil the production.APICaller interface.
+func (r *Recorder) Call(req *http.Request) (*http.Response, error) {
+	return r.record(req, r.Caller.Call)
+}
+
+// CallRaw method to fulfil the production.APICaller interface.
+func (r *Recorder) CallRaw(req *http.Request) (*http.Response, error) {
+	return r.record(req, r.Caller.CallRaw)
+}
+
+// SetNewAPIToken method to fulfil the production.APICaller interface. Token requests are not recorded.
+func (r *Recorder) SetNewAPIToken(req *http.Request) error {
+	return r.Caller.SetNewAPIToken(req)
+}
+
+// Returns a getter for file.PlainGetter recording downloads made through get.
+// Downloads are made using http.Get if get is nil.
+func (r *Recorder) PlainGetter(get func(url string) (*http.Response, error)) func(url string) (*http.Response, error) {
+	if get == nil {
+		get = http.Get
+	}
+
+	return func(url string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.record(req, func(*http.Request) (*http.Response, error) {
+			return get(url)
+		})
+	}
+}
+
+// Returns a getter for file.PlainContextGetter recording downloads made through get.
+// Downloads are made using http.DefaultClient if get is nil.
+func (r *Recorder) PlainContextGetter(get func(ctx context.Context, url string) (*http.Response, error)) func(ctx context.Context, url string) (*http.Response, error) {
+	return func(ctx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.record(req, func(req *http.Request) (*http.Response, error) {
+			if get == nil {
+				return http.DefaultClient.Do(req)
+			}
+			return get(ctx, url)
+		})
+	}
+}
+
+// Returns the recorded interactions.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := &Cassette{Interactions: append([]*Interaction{}, r.cassette.Interactions...)}
+	return c
+}
+
+// Writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	return r.Cassette().Save(r.Path)
+}
+
+// Performs call and records the interaction. Failed calls without a response are not recorded.
+func (r *Recorder) record(req *http.Request, call func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	i := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  NormalizeQuery(scrubQuery(r.ScrubQuery, req.URL.RawQuery)),
+		},
+	}
+
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(body)
+			body.Close()
+			i.Request.Body = r.scrub(string(b))
+		}
+	}
+
+	resp, err := call(req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	i.Response.StatusCode = resp.StatusCode
+
+	for _, v := range recordedHeaders {
+		if h := resp.Header.Values(v); len(h) > 0 {
+			if i.Response.Header == nil {
+				i.Response.Header = map[string][]string{}
+			}
+			i.Response.Header[v] = h
+		}
+	}
+
+	if resp.Body != nil {
+		b, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+		i.Response.setBody(b, r.scrub)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Scrubs s using Scrub.
+func (r *Recorder) scrub(s string) string {
+	if r.Scrub == nil {
+		return s
+	}
+	return r.Scrub(s)
+}
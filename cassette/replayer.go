@@ -0,0 +1,164 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// UnmatchedError is returned by a Replayer for requests not matching any unused recorded interaction.
+type UnmatchedError struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// Error method to fulfil the error interface.
+func (e *UnmatchedError) Error() string {
+	s := fmt.Sprintf(`cassette: no recorded interaction matches %s "%s`, e.Method, e.Path)
+	if e.Query != "" {
+		s += "?" + e.Query
+	}
+	return s + `"`
+}
+
+// Replayer is an APICaller serving recorded interactions instead of making calls.
+// Requests are matched on method, path and normalized query. Each recorded interaction is served once, in order.
+// Requests that do not match get an UnmatchedError, and are also reported by Err.
+type Replayer struct {
+	// Scrubs queries before matching them, as they were when recorded. Defaults to production.RedactQuery.
+	ScrubQuery func(query string) string
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	used      []bool
+	unmatched []error
+}
+
+// Creates new Replayer serving the interactions of the cassette file at path.
+func NewReplayer(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCassetteReplayer(c), nil
+}
+
+// Creates new Replayer serving the interactions of c.
+func NewCassetteReplayer(c *Cassette) *Replayer {
+	return &Replayer{
+		ScrubQuery: production.RedactQuery,
+		cassette:   c,
+		used:       make([]bool, len(c.Interactions)),
+	}
+}
+
+// Call method to fulfil the production.APICaller interface.
+func (r *Replayer) Call(req *http.Request) (*http.Response, error) {
+	return r.replay(req)
+}
+
+// CallRaw method to fulfil the production.APICaller interface.
+func (r *Replayer) CallRaw(req *http.Request) (*http.Response, error) {
+	return r.replay(req)
+}
+
+// SetNewAPIToken method to fulfil the production.APICaller interface. Does nothing.
+func (r *Replayer) SetNewAPIToken(req *http.Request) error {
+	return nil
+}
+
+// Returns a getter for file.PlainGetter replaying recorded downloads.
+func (r *Replayer) PlainGetter() func(url string) (*http.Response, error) {
+	return func(url string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return r.replay(req)
+	}
+}
+
+// Returns a getter for file.PlainContextGetter replaying recorded downloads.
+func (r *Replayer) PlainContextGetter() func(ctx context.Context, url string) (*http.Response, error) {
+	return func(ctx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		return r.replay(req)
+	}
+}
+
+// Returns an error listing all requests that did not match a recorded interaction, or nil if all matched.
+func (r *Replayer) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(r.unmatched...)
+}
+
+// Returns the recorded interactions that have not been served.
+func (r *Replayer) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var l []*Interaction
+	for k, v := range r.cassette.Interactions {
+		if !r.used[k] {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// Serves the first unused interaction matching req.
+func (r *Replayer) replay(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	query := NormalizeQuery(scrubQuery(r.ScrubQuery, req.URL.RawQuery))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.cassette.Interactions {
+		if r.used[k] || !matches(v.Request, req.Method, req.URL.Path, query) {
+			continue
+		}
+		r.used[k] = true
+
+		if req.Body != nil {
+			io.Copy(io.Discard, req.Body)
+			req.Body.Close()
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", v.Response.StatusCode, http.StatusText(v.Response.StatusCode)),
+			StatusCode:    v.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header(v.Response.Header).Clone(),
+			Body:          io.NopCloser(bytes.NewReader(v.Response.body())),
+			ContentLength: int64(len(v.Response.body())),
+			Request:       req,
+		}, nil
+	}
+
+	err := &UnmatchedError{Method: req.Method, Path: req.URL.Path, Query: query}
+	r.unmatched = append(r.unmatched, err)
+	return nil, err
+}
+
+// Checks if recorded request matches method, path and normalized query.
+func matches(recorded Request, method string, path string, query string) bool {
+	return recorded.Method == method && recorded.Path == path && recorded.Query == query
+}
@@ -0,0 +1,143 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Getter defines how a client performs GET calls against the production API.
+type Getter interface {
+	Get(endpoint Endpointer, model interface{}, queryParams ...func(q url.Values)) error
+}
+
+// ContextGetter defines how a client performs context aware GET calls against the production API.
+type ContextGetter interface {
+	GetContext(ctx context.Context, endpoint Endpointer, model interface{}, queryParams ...func(q url.Values)) error
+}
+
+// Poster defines how a client performs POST calls against the production API.
+type Poster interface {
+	Post(endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// ContextPoster defines how a client performs context aware POST calls against the production API.
+type ContextPoster interface {
+	PostContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// Putter defines how a client performs PUT calls against the production API.
+type Putter interface {
+	Put(endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// ContextPutter defines how a client performs context aware PUT calls against the production API.
+type ContextPutter interface {
+	PutContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// Deleter defines how a client performs DELETE calls against the production API.
+type Deleter interface {
+	Delete(endpoint Endpointer, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// ContextDeleter defines how a client performs context aware DELETE calls against the production API.
+type ContextDeleter interface {
+	DeleteContext(ctx context.Context, endpoint Endpointer, result interface{}, headers ...func(h *http.Header)) error
+}
+
+// Returns g as a ContextGetter.
+// If g does not support contexts itself the context is only checked before the call is made.
+func GetterWithContext(g Getter) ContextGetter {
+	if cg, ok := g.(ContextGetter); ok {
+		return cg
+	}
+	return getterWithContext{g}
+}
+
+// Returns p as a ContextPoster.
+// If p does not support contexts itself the context is only checked before the call is made.
+func PosterWithContext(p Poster) ContextPoster {
+	if cp, ok := p.(ContextPoster); ok {
+		return cp
+	}
+	return posterWithContext{p}
+}
+
+// Returns p as a ContextPutter.
+// If p does not support contexts itself the context is only checked before the call is made.
+func PutterWithContext(p Putter) ContextPutter {
+	if cp, ok := p.(ContextPutter); ok {
+		return cp
+	}
+	return putterWithContext{p}
+}
+
+// Returns d as a ContextDeleter.
+// If d does not support contexts itself the context is only checked before the call is made.
+func DeleterWithContext(d Deleter) ContextDeleter {
+	if cd, ok := d.(ContextDeleter); ok {
+		return cd
+	}
+	return deleterWithContext{d}
+}
+
+// Adapts a Getter to the ContextGetter interface.
+type getterWithContext struct {
+	Getter
+}
+
+// Returns the Tracer of the adapted Getter, if any.
+func (g getterWithContext) CallTracer() Tracer {
+	return TracerOf(g.Getter)
+}
+
+// Returns the Metrics of the adapted Getter, if any.
+func (g getterWithContext) CallMetrics() Metrics {
+	return MetricsOf(g.Getter)
+}
+
+func (g getterWithContext) GetContext(ctx context.Context, endpoint Endpointer, model interface{}, queryParams ...func(q url.Values)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.Get(endpoint, model, queryParams...)
+}
+
+// Adapts a Poster to the ContextPoster interface.
+type posterWithContext struct {
+	Poster
+}
+
+func (p posterWithContext) PostContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Post(endpoint, payload, result, headers...)
+}
+
+// Adapts a Putter to the ContextPutter interface.
+type putterWithContext struct {
+	Putter
+}
+
+func (p putterWithContext) PutContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.Put(endpoint, payload, result, headers...)
+}
+
+// Adapts a Deleter to the ContextDeleter interface.
+type deleterWithContext struct {
+	Deleter
+}
+
+func (d deleterWithContext) DeleteContext(ctx context.Context, endpoint Endpointer, result interface{}, headers ...func(h *http.Header)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.Delete(endpoint, result, headers...)
+}
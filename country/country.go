@@ -6,10 +6,10 @@
 package country
 
 import (
-	"fmt"
+	"context"
 	"github.com/publitsweden/ProductionAPIGoSDK"
+	"iter"
 	"net/url"
-	"strings"
 )
 
 // Country attributes constants.
@@ -33,9 +33,10 @@ type Country struct {
 }
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
 
 // Endpoint enumeration type.
 type Endpoint int
@@ -46,37 +47,63 @@ const (
 	SHOW
 )
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX: "countries",
-	SHOW:  "countries/%v",
-}
+// Generic resource backing the package functions.
+var resource = production.NewResource[Country, IndexResponse]("countries")
 
 // Returns Country from Publits production API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*Country, error) {
-	co := &Country{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, co, queryParams...)
-	return co, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
+}
+
+// Returns Country from Publits production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*Country, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
 type CountriesList []*Country
 
 // Index response object.
-type IndexResponse struct {
-	Count int           `json:"count"`
-	Next  string        `json:"next"`
-	Prev  string        `json:"prev"`
-	Data  CountriesList `json:"data"`
-}
+type IndexResponse production.IndexResponse[CountriesList]
 
 // Indexes Countries from the Publit API.
 // Returns IndexResponse where data contains StatusList.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Indexes Countries from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
+
+// Iterates all Countries matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[*Country, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all Countries matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[*Country, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]*Country, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
+	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all Countries matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) (CountriesList, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all Countries matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) (CountriesList, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return CountriesList(l), err
 }
 
 // Resource struct
@@ -87,12 +114,8 @@ type Resource struct {
 
 // Method to Resource that fullfils the Enpointer interface as stated in production.
 func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
-
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+	if r.Endpoint == SHOW {
+		return resource.Endpoint(r.Id).GetEndpoint()
 	}
-
-	return end
+	return resource.Endpoint(0).GetEndpoint()
 }
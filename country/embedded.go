@@ -0,0 +1,366 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package country
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ISO 3166-1 countries, one per line: alpha-2, alpha-3, numeric, English short name and native name separated by tabs.
+//
+//go:embed iso3166.tsv
+var iso3166 string
+
+// Embedded countries. Publit IDs are not part of ISO 3166 and are learned through Sync.
+var embedded = loadEmbedded(iso3166)
+
+// Embedded ISO 3166-1 dataset with lookup indexes.
+type dataset struct {
+	mu        sync.RWMutex
+	countries CountriesList
+	byISO2    map[string]*Country
+	byISO3    map[string]*Country
+	byNumeric map[string]*Country
+	byName    map[string]*Country
+}
+
+// Parses the embedded dataset.
+func loadEmbedded(tsv string) *dataset {
+	d := &dataset{}
+
+	for k, line := range strings.Split(tsv, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		f := strings.Split(line, "\t")
+		if len(f) != 5 {
+			panic(fmt.Sprintf("country: malformed line %d of embedded dataset: %q", k+1, line))
+		}
+
+		d.countries = append(d.countries, &Country{ISO2: f[0], ISO3: f[1], ISONUM: f[2], Name: f[3], NativeName: f[4]})
+	}
+
+	d.index()
+	return d
+}
+
+// Builds the lookup indexes. Must be called with mu held for writing, or before the dataset is shared.
+func (d *dataset) index() {
+	d.byISO2 = make(map[string]*Country, len(d.countries))
+	d.byISO3 = make(map[string]*Country, len(d.countries))
+	d.byNumeric = make(map[string]*Country, len(d.countries))
+	d.byName = make(map[string]*Country, 3*len(d.countries))
+
+	for _, v := range d.countries {
+		d.byISO2[v.ISO2] = v
+		d.byName[foldName(v.Name)] = v
+
+		// Countries added by Sync may lack codes.
+		if v.ISO3 != "" {
+			d.byISO3[v.ISO3] = v
+		}
+		if v.ISONUM != "" {
+			d.byNumeric[v.ISONUM] = v
+		}
+	}
+
+	// Shortened names are left out if they are ambiguous, e.g. "Virgin Islands".
+	short := map[string]*Country{}
+	for _, v := range d.countries {
+		for _, n := range nameVariants(v.Name)[1:] {
+			key := foldName(n)
+			if c, ok := short[key]; ok && c != v {
+				short[key] = nil
+				continue
+			}
+			short[key] = v
+		}
+	}
+
+	for alias, code := range nameAliases {
+		short[foldName(alias)] = d.byISO2[code]
+	}
+
+	// Native names do not shadow English names, nor do shortened names shadow full names.
+	for key, v := range short {
+		if _, ok := d.byName[key]; !ok && v != nil {
+			d.byName[key] = v
+		}
+	}
+
+	for _, v := range d.countries {
+		key := foldName(v.NativeName)
+		if _, ok := d.byName[key]; !ok {
+			d.byName[key] = v
+		}
+	}
+}
+
+// Returns a copy of the country from index m at key, or nil if there is none.
+func (d *dataset) lookup(m func(d *dataset) map[string]*Country, key string) *Country {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	v, ok := m(d)[key]
+	if !ok {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// Returns the country with ISO 3166-1 alpha-2 code from the embedded dataset, e.g. "SE". Returns nil if there is none.
+func ByISO2(code string) *Country {
+	return embedded.lookup(func(d *dataset) map[string]*Country { return d.byISO2 }, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// Returns the country with ISO 3166-1 alpha-3 code from the embedded dataset, e.g. "SWE". Returns nil if there is none.
+func ByISO3(code string) *Country {
+	return embedded.lookup(func(d *dataset) map[string]*Country { return d.byISO3 }, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// Returns the country with ISO 3166-1 numeric code from the embedded dataset, e.g. "752". Leading zeros may be left out.
+// Returns nil if there is none.
+func ByNumeric(code string) *Country {
+	code = strings.TrimSpace(code)
+	if len(code) < 3 {
+		code = strings.Repeat("0", 3-len(code)) + code
+	}
+	return embedded.lookup(func(d *dataset) map[string]*Country { return d.byNumeric }, code)
+}
+
+// Returns the country with English or native name from the embedded dataset, e.g. "Sweden" or "Sverige".
+// Case, diacritics and punctuation are ignored. Qualifiers of official names may be left out or put first,
+// e.g. "Bolivia" and "Republic of Korea" are found. Returns nil if there is none.
+func ByName(name string) *Country {
+	return embedded.lookup(func(d *dataset) map[string]*Country { return d.byName }, foldName(name))
+}
+
+// Returns the country with Publit ID from the embedded dataset. Publit IDs are only known after Sync has been run,
+// before that nil is returned, as it is if there is no country with the ID.
+func ByID(id int) *Country {
+	embedded.mu.RLock()
+	defer embedded.mu.RUnlock()
+
+	if id == 0 {
+		return nil
+	}
+	for _, v := range embedded.countries {
+		if v.ID == id {
+			c := *v
+			return &c
+		}
+	}
+	return nil
+}
+
+// Returns all countries of the embedded dataset, ordered by alpha-2 code.
+func Embedded() CountriesList {
+	embedded.mu.RLock()
+	defer embedded.mu.RUnlock()
+
+	l := make(CountriesList, len(embedded.countries))
+	for k, v := range embedded.countries {
+		c := *v
+		l[k] = &c
+	}
+	return l
+}
+
+// IDChange is a country whose Publit ID differs from the one previously known.
+type IDChange struct {
+	Country *Country
+	// Previously known Publit ID.
+	PreviousID int
+}
+
+// SyncReport is the outcome of reconciling the embedded dataset with the Publit API.
+type SyncReport struct {
+	// Countries whose Publit ID differs from the one previously learned.
+	Changed []IDChange
+	// Number of countries whose Publit ID was not known before.
+	Learned int
+	// Countries of the Publit API missing from the embedded dataset. They are added to it.
+	Unknown CountriesList
+	// Countries of the embedded dataset missing from the Publit API.
+	Missing CountriesList
+}
+
+// Reconciles the embedded dataset with the countries of the Publit API, matched by alpha-2 code.
+// The Publit IDs of the embedded countries are updated and countries differing from the dataset are reported.
+func Sync(c ProductionAPIGetter) (*SyncReport, error) {
+	return SyncContext(context.Background(), production.GetterWithContext(c))
+}
+
+// Reconciles the embedded dataset with the countries of the Publit API, matched by alpha-2 code.
+// The Publit IDs of the embedded countries are updated and countries differing from the dataset are reported.
+// The request is aborted if ctx is cancelled or its deadline expires, in which case the dataset is left unchanged.
+func SyncContext(ctx context.Context, c ProductionAPIContextGetter) (*SyncReport, error) {
+	l, err := IndexAllContext(ctx, c, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return embedded.sync(l), nil
+}
+
+// Reconciles the dataset with the countries in l.
+func (d *dataset) sync(l CountriesList) *SyncReport {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r := &SyncReport{}
+	seen := map[string]bool{}
+
+	for _, v := range l {
+		code := strings.ToUpper(v.ISO2)
+		seen[code] = true
+
+		e, ok := d.byISO2[code]
+		if !ok {
+			r.Unknown = append(r.Unknown, v)
+			if code == "" {
+				continue
+			}
+			c := *v
+			c.ISO2 = code
+			d.countries = append(d.countries, &c)
+			continue
+		}
+
+		switch {
+		case e.ID == v.ID:
+		case e.ID == 0:
+			r.Learned++
+		default:
+			c := *e
+			c.ID = v.ID
+			r.Changed = append(r.Changed, IDChange{Country: &c, PreviousID: e.ID})
+		}
+		e.ID = v.ID
+	}
+
+	for _, v := range d.countries {
+		if !seen[v.ISO2] {
+			c := *v
+			r.Missing = append(r.Missing, &c)
+		}
+	}
+
+	if len(r.Unknown) > 0 {
+		sort.Slice(d.countries, func(i, j int) bool { return d.countries[i].ISO2 < d.countries[j].ISO2 })
+		d.index()
+	}
+
+	return r
+}
+
+// Returns name and the variants it can be looked up by: without parenthesised qualifiers and with inverted
+// qualifiers put first, e.g. "Bolivia (Plurinational State of)" gives "Bolivia" and "Korea, Republic of" gives "Republic of Korea".
+func nameVariants(name string) []string {
+	l := []string{name}
+
+	if i := strings.Index(name, " ("); i > 0 {
+		l = append(l, name[:i])
+	}
+
+	if i := strings.LastIndex(name, ", "); i > 0 {
+		l = append(l, name[i+2:]+" "+name[:i])
+	}
+
+	return l
+}
+
+// Common English names of countries whose ISO 3166 names differ, by alpha-2 code.
+// Ambiguous names map to no code.
+var nameAliases = map[string]string{
+	"Burma":          "MM",
+	"Cape Verde":     "CV",
+	"Czech Republic": "CZ",
+	"East Timor":     "TL",
+	"Great Britain":  "GB",
+	"Ivory Coast":    "CI",
+	"Korea":          "",
+	"Laos":           "LA",
+	"Macedonia":      "MK",
+	"North Korea":    "KP",
+	"Palestine":      "PS",
+	"Russia":         "RU",
+	"South Korea":    "KR",
+	"Swaziland":      "SZ",
+	"Syria":          "SY",
+	"Taiwan":         "TW",
+	"Turkey":         "TR",
+	"Vatican City":   "VA",
+	"Vietnam":        "VN",
+}
+
+// Letters with diacritics and the letters they are folded to.
+var foldedLetters = map[string]string{
+	"a":  "àáâãäåāăąǎạảấầẩẫậắằẳẵặ",
+	"c":  "çćĉċč",
+	"d":  "ďđð",
+	"e":  "èéêëēĕėęěẹẻẽếềểễệə",
+	"g":  "ĝğġģ",
+	"h":  "ĥħ",
+	"i":  "ìíîïĩīĭįıỉị",
+	"j":  "ĵ",
+	"k":  "ķ",
+	"l":  "ĺļľŀł",
+	"n":  "ñńņňŉ",
+	"o":  "òóôõöøōŏőơọỏốồổỗộớờởỡợ",
+	"r":  "ŕŗř",
+	"s":  "śŝşšș",
+	"t":  "ţťŧț",
+	"u":  "ùúûüũūŭůűųưụủứừửữự",
+	"w":  "ŵ",
+	"y":  "ýÿŷỳỵỷỹ",
+	"z":  "źżž",
+	"ae": "æ",
+	"oe": "œ",
+	"ss": "ß",
+	"th": "þ",
+}
+
+// Folding of single letters.
+var foldTable = func() map[rune]string {
+	m := map[rune]string{}
+	for to, from := range foldedLetters {
+		for _, r := range from {
+			m[r] = to
+		}
+	}
+	return m
+}()
+
+// Folds name for case, diacritic and punctuation insensitive matching.
+func foldName(name string) string {
+	var b strings.Builder
+	space := true
+
+	for _, r := range strings.ToLower(strings.ReplaceAll(name, "&", " and ")) {
+		switch {
+		case foldTable[r] != "":
+			b.WriteString(foldTable[r])
+			space = false
+		case r == '\'' || r == '’' || unicode.Is(unicode.Lm, r) || unicode.Is(unicode.Mn, r):
+			// Apostrophes, modifier letters and combining marks, e.g. the okina in "Oʻzbekiston".
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case !space:
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,136 @@
+package country_test
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	. "github.com/publitsweden/ProductionAPIGoSDK/country"
+	"net/url"
+	"testing"
+)
+
+func TestCanLookUpEmbeddedCountriesByCode(t *testing.T) {
+	t.Parallel()
+	tests := map[string]*Country{
+		"ISO2 se":      ByISO2("se"),
+		"ISO3 SWE":     ByISO3("SWE"),
+		"Numeric 752":  ByNumeric("752"),
+		"Name Sweden":  ByName("Sweden"),
+		"Name SVERIGE": ByName("SVERIGE"),
+	}
+
+	for name, c := range tests {
+		if c == nil || c.ISO2 != "SE" || c.ISO3 != "SWE" || c.ISONUM != "752" || c.Name != "Sweden" || c.NativeName != "Sverige" {
+			t.Errorf(`%s: Expected Sweden but got: "%v"`, name, c)
+		}
+	}
+
+	if c := ByNumeric("4"); c == nil || c.ISO2 != "AF" {
+		t.Errorf(`Expected numeric code without leading zeros to find Afghanistan but got: "%v"`, c)
+	}
+
+	if c := ByISO2("XX"); c != nil {
+		t.Errorf(`Expected no country for unknown code but got: "%v"`, c)
+	}
+}
+
+func TestCanLookUpEmbeddedCountriesByName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"cote d'ivoire":     "CI",
+		"Côte d’Ivoire":     "CI",
+		"osterreich":        "AT",
+		"ÅLAND":             "AX",
+		"Türkiye":           "TR",
+		"Turkey":            "TR",
+		"Bolivia":           "BO",
+		"Republic of Korea": "KR",
+		"South Korea":       "KR",
+		"Uzbekistan":        "UZ",
+		"Ozbekiston":        "UZ",
+		"Deutschland":       "DE",
+		"Россия":            "RU",
+		"Trinidad & Tobago": "TT",
+		"Niger":             "NE",
+		"Nigeria":           "NG",
+	}
+
+	for name, code := range tests {
+		if c := ByName(name); c == nil || c.ISO2 != code {
+			t.Errorf(`Expected "%s" to find %s but got: "%v"`, name, code, c)
+		}
+	}
+
+	for _, name := range []string{"Korea", "Virgin Islands"} {
+		if c := ByName(name); c != nil {
+			t.Errorf(`Expected ambiguous name "%s" to find nothing but got: "%v"`, name, c)
+		}
+	}
+}
+
+func TestEmbeddedCountriesAreComplete(t *testing.T) {
+	t.Parallel()
+	l := Embedded()
+
+	// Sync may add countries.
+	if len(l) < 249 {
+		t.Errorf("Expected all 249 ISO 3166-1 countries but got %d.", len(l))
+	}
+
+	for _, v := range l {
+		if ByName(v.Name) == nil {
+			t.Errorf(`Expected "%s" to be found by name.`, v.Name)
+		}
+	}
+
+	// Lookups return copies.
+	l[0].Name = "Changed"
+	if Embedded()[0].Name == "Changed" {
+		t.Error("Expected embedded dataset to be unaffected by changes to returned countries.")
+	}
+}
+
+func TestSyncReconcilesEmbeddedCountriesWithAPI(t *testing.T) {
+	countries := CountriesList{
+		&Country{ID: 205, ISO2: "SE", Name: "Sweden"},
+		&Country{ID: 300, ISO2: "XK", Name: "Kosovo"},
+	}
+	c := &MockProductionAPIClient{
+		T: t,
+		GetCall: func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
+			model.(*IndexResponse).Data = countries
+		},
+	}
+
+	r, err := Sync(c)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if r.Learned != 1 || len(r.Changed) != 0 {
+		t.Errorf("Expected the ID of Sweden to be learned but got: %+v", r)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0].ISO2 != "XK" {
+		t.Errorf(`Expected Kosovo to be unknown but got: "%v"`, r.Unknown)
+	}
+	if len(r.Missing) != 248 {
+		t.Errorf("Expected 248 missing countries but got %d.", len(r.Missing))
+	}
+	if se := ByISO2("SE"); se.ID != 205 {
+		t.Errorf("Expected Sweden to get Publit ID 205 but got %d.", se.ID)
+	}
+	if se := ByID(205); se == nil || se.ISO2 != "SE" {
+		t.Errorf(`Expected Sweden to be found by Publit ID but got: "%v"`, se)
+	}
+	if xk := ByName("Kosovo"); xk == nil || xk.ID != 300 {
+		t.Errorf(`Expected Kosovo to be added but got: "%v"`, xk)
+	}
+
+	countries[0] = &Country{ID: 206, ISO2: "SE", Name: "Sweden"}
+	r, err = Sync(c)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if len(r.Changed) != 1 || r.Changed[0].PreviousID != 205 || r.Changed[0].Country.ID != 206 {
+		t.Errorf("Expected changed ID of Sweden to be reported but got: %+v", r.Changed)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package country
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// Region of the world a country belongs to, following the UN M49 continental regions except that Cyprus is in Europe.
+type Region string
+
+// Region constants.
+const (
+	REGION_AFRICA     Region = "Africa"
+	REGION_AMERICAS   Region = "Americas"
+	REGION_ANTARCTICA Region = "Antarctica"
+	REGION_ASIA       Region = "Asia"
+	REGION_EUROPE     Region = "Europe"
+	REGION_OCEANIA    Region = "Oceania"
+)
+
+// Zone is a group of countries relevant for shipping.
+type Zone string
+
+// Zone constants.
+const (
+	// Member states of the European Union.
+	ZONE_EU Zone = "eu"
+	// Customs territory of the European Union: the member states, Monaco and territories such as Åland and the French overseas departments.
+	ZONE_EU_CUSTOMS_UNION Zone = "eu_customs_union"
+	// VAT area of the European Union: the member states and Monaco, but not territories outside it such as Åland.
+	// Goods sent within it need no customs declaration.
+	ZONE_EU_VAT_AREA Zone = "eu_vat_area"
+	// European Economic Area: the member states of the European Union, Iceland, Liechtenstein and Norway.
+	ZONE_EEA Zone = "eea"
+	// Nordic postal zone: Denmark, Finland, Iceland, Norway, Sweden, the Faroe Islands, Greenland and Åland.
+	ZONE_NORDIC Zone = "nordic"
+)
+
+// Customs declaration forms of the Universal Postal Union.
+const (
+	CUSTOMS_FORM_CN22 = "CN22"
+	CUSTOMS_FORM_CN23 = "CN23"
+)
+
+// Highest value, in special drawing rights (SDR), of items that can be declared on a CN22. Above it a CN23 is required.
+const CN22_MAX_VALUE_SDR = 300
+
+// Member states of the European Union.
+var euMembers []string = []string{
+	"AT", "BE", "BG", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR", "HR", "HU",
+	"IE", "IT", "LT", "LU", "LV", "MT", "NL", "PL", "PT", "RO", "SE", "SI", "SK",
+}
+
+// Countries of each zone by alpha-2 code.
+var zoneMembers map[Zone][]string = map[Zone][]string{
+	ZONE_EU:               euMembers,
+	ZONE_EU_CUSTOMS_UNION: append([]string{"MC", "AX", "GF", "GP", "MQ", "RE", "YT", "MF"}, euMembers...),
+	ZONE_EU_VAT_AREA:      append([]string{"MC"}, euMembers...),
+	ZONE_EEA:              append([]string{"IS", "LI", "NO"}, euMembers...),
+	ZONE_NORDIC:           {"DK", "FI", "IS", "NO", "SE", "FO", "GL", "AX"},
+}
+
+// Metadata holds information about a country used for shipping and addressing.
+type Metadata struct {
+	Region Region
+	// ISO 4217 code of the currency, e.g. "SEK". Empty for uninhabited territories.
+	Currency string
+	// International calling code without "+", e.g. "46". Empty if there is none.
+	CallingCode string
+	// Set if postal codes are in general use.
+	PostalCodes bool
+	// Zones the country belongs to.
+	Zones []Zone
+}
+
+// Country metadata, one per line: alpha-2 code, region, currency, calling code and 1 if postal codes are used, separated by tabs.
+//
+//go:embed metadata.tsv
+var metadataTSV string
+
+// Metadata by alpha-2 code.
+var metadata = loadMetadata(metadataTSV)
+
+// Parses the embedded metadata.
+func loadMetadata(tsv string) map[string]*Metadata {
+	m := map[string]*Metadata{}
+
+	for k, line := range strings.Split(tsv, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		f := strings.Split(line, "\t")
+		if len(f) != 5 {
+			panic(fmt.Sprintf("country: malformed line %d of embedded metadata: %q", k+1, line))
+		}
+
+		m[f[0]] = &Metadata{Region: Region(f[1]), Currency: f[2], CallingCode: f[3], PostalCodes: f[4] == "1"}
+	}
+
+	for _, z := range []Zone{ZONE_EU, ZONE_EU_CUSTOMS_UNION, ZONE_EU_VAT_AREA, ZONE_EEA, ZONE_NORDIC} {
+		for _, v := range zoneMembers[z] {
+			m[v].Zones = append(m[v].Zones, z)
+		}
+	}
+
+	return m
+}
+
+// Returns the metadata of the country with ISO 3166-1 alpha-2 code. Returns nil if there is none.
+func MetadataOf(iso2 string) *Metadata {
+	m, ok := metadata[strings.ToUpper(strings.TrimSpace(iso2))]
+	if !ok {
+		return nil
+	}
+	c := *m
+	c.Zones = append([]Zone(nil), m.Zones...)
+	return &c
+}
+
+// Returns the metadata of the country. Returns nil if the country is nil or its alpha-2 code is unknown.
+func (c *Country) Metadata() *Metadata {
+	if c == nil {
+		return nil
+	}
+	return MetadataOf(c.ISO2)
+}
+
+// Checks if the country belongs to zone.
+func (c *Country) InZone(zone Zone) bool {
+	m := c.Metadata()
+	if m == nil {
+		return false
+	}
+
+	for _, v := range m.Zones {
+		if v == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks if items sent to the country from origin need a CN22 or CN23 customs declaration.
+// Items sent within a country or within the EU VAT area need none. Items sent to or from an unknown country,
+// e.g. a print order without a loaded delivery country, are assumed to need one.
+//
+//	if po.DeliveryCountry.RequiresCustomsDeclaration(country.ByISO2("SE")) {
+func (c *Country) RequiresCustomsDeclaration(origin *Country) bool {
+	if c.Metadata() == nil || origin.Metadata() == nil {
+		return true
+	}
+
+	if strings.EqualFold(c.ISO2, origin.ISO2) {
+		return false
+	}
+
+	return !(c.InZone(ZONE_EU_VAT_AREA) && origin.InZone(ZONE_EU_VAT_AREA))
+}
+
+// Returns the customs declaration form needed for items of valueSDR special drawing rights sent to the country from origin:
+// CUSTOMS_FORM_CN22, CUSTOMS_FORM_CN23 or an empty string if no declaration is needed.
+func (c *Country) CustomsForm(origin *Country, valueSDR float64) string {
+	if !c.RequiresCustomsDeclaration(origin) {
+		return ""
+	}
+	if valueSDR > CN22_MAX_VALUE_SDR {
+		return CUSTOMS_FORM_CN23
+	}
+	return CUSTOMS_FORM_CN22
+}
@@ -0,0 +1,84 @@
+package country_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK/country"
+	"testing"
+)
+
+func TestCountryMetadata(t *testing.T) {
+	t.Parallel()
+	m := ByISO2("SE").Metadata()
+
+	if m == nil || m.Region != REGION_EUROPE || m.Currency != "SEK" || m.CallingCode != "46" || !m.PostalCodes {
+		t.Errorf(`Expected metadata of Sweden but got: "%+v"`, m)
+	}
+
+	if m := MetadataOf("hk"); m == nil || m.PostalCodes || m.Currency != "HKD" || m.Region != REGION_ASIA {
+		t.Errorf(`Expected metadata of Hong Kong but got: "%+v"`, m)
+	}
+
+	if m := (&Country{ISO2: "XX"}).Metadata(); m != nil {
+		t.Errorf(`Expected no metadata for unknown country but got: "%+v"`, m)
+	}
+
+	for _, v := range Embedded() {
+		if v.ISO2 != "XK" && v.Metadata() == nil {
+			t.Errorf("Expected metadata for %s.", v.ISO2)
+		}
+	}
+}
+
+func TestCountryZones(t *testing.T) {
+	t.Parallel()
+	tests := map[string]map[Zone]bool{
+		"SE": {ZONE_EU: true, ZONE_EU_CUSTOMS_UNION: true, ZONE_EU_VAT_AREA: true, ZONE_EEA: true, ZONE_NORDIC: true},
+		"NO": {ZONE_EU: false, ZONE_EU_CUSTOMS_UNION: false, ZONE_EU_VAT_AREA: false, ZONE_EEA: true, ZONE_NORDIC: true},
+		"AX": {ZONE_EU: false, ZONE_EU_CUSTOMS_UNION: true, ZONE_EU_VAT_AREA: false, ZONE_EEA: false, ZONE_NORDIC: true},
+		"MC": {ZONE_EU: false, ZONE_EU_CUSTOMS_UNION: true, ZONE_EU_VAT_AREA: true, ZONE_EEA: false, ZONE_NORDIC: false},
+		"GB": {ZONE_EU: false, ZONE_EU_CUSTOMS_UNION: false, ZONE_EU_VAT_AREA: false, ZONE_EEA: false, ZONE_NORDIC: false},
+	}
+
+	for code, zones := range tests {
+		c := ByISO2(code)
+		for z, expected := range zones {
+			if c.InZone(z) != expected {
+				t.Errorf(`Expected %s in zone "%s" to be %v.`, code, z, expected)
+			}
+		}
+	}
+}
+
+func TestRequiresCustomsDeclaration(t *testing.T) {
+	t.Parallel()
+	se := ByISO2("SE")
+	tests := map[string]bool{
+		"SE": false,
+		"DE": false,
+		"MC": false,
+		"NO": true,
+		"AX": true,
+		"GB": true,
+		"US": true,
+	}
+
+	for code, expected := range tests {
+		if ByISO2(code).RequiresCustomsDeclaration(se) != expected {
+			t.Errorf("Expected customs declaration from SE to %s to be required: %v.", code, expected)
+		}
+	}
+
+	var unknown *Country
+	if !unknown.RequiresCustomsDeclaration(se) {
+		t.Error("Expected customs declaration to be required for unknown country.")
+	}
+
+	if f := ByISO2("NO").CustomsForm(se, 100); f != CUSTOMS_FORM_CN22 {
+		t.Errorf(`Expected CN22 but got: "%s"`, f)
+	}
+	if f := ByISO2("NO").CustomsForm(se, 301); f != CUSTOMS_FORM_CN23 {
+		t.Errorf(`Expected CN23 but got: "%s"`, f)
+	}
+	if f := ByISO2("DE").CustomsForm(se, 301); f != "" {
+		t.Errorf(`Expected no customs form but got: "%s"`, f)
+	}
+}
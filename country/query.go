@@ -0,0 +1,36 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package country
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"strings"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, Name, NativeName, ISO2, ISO3, ISONUM,
+}
+
+// QueryBuilder builds query parameters for indexing Countries.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for Countries.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, nil)
+	return q
+}
+
+// Filters on Countries with ISO 3166-1 alpha-2 code.
+func (q *QueryBuilder) ISO2(code string) *QueryBuilder {
+	return q.Where(ISO2, production.OPERATOR_EQUAL, strings.ToUpper(code))
+}
+
+// Filters on Countries with ISO 3166-1 alpha-3 code.
+func (q *QueryBuilder) ISO3(code string) *QueryBuilder {
+	return q.Where(ISO3, production.OPERATOR_EQUAL, strings.ToUpper(code))
+}
@@ -5,13 +5,12 @@
 package deliverynumber
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
-	"net/http"
+	"iter"
 	"net/url"
-	"strings"
 )
 
 // DeliveryNumber attribute constants.
@@ -52,34 +51,32 @@ const (
 	DELETE
 )
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX:  "print_order_delivery_numbers",
-	SHOW:   "print_order_delivery_numbers/%v",
-	POST:   "print_order_delivery_numbers",
-	PUT:    "print_order_delivery_numbers/%v",
-	DELETE: "print_order_delivery_numbers/%v",
-}
+// Generic resource backing the package functions.
+var resource = production.NewResource[DeliveryNumber, IndexResponse]("print_order_delivery_numbers")
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
 
 // ProductionAPIPoster defines how the client should perform POST calls.
-type ProductionAPIPoster interface {
-	Post(endpoint production.Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
-}
+type ProductionAPIPoster = production.Poster
 
 // ProductionAPIPoster defines how the client should perform PUT calls.
-type ProductionAPIPutter interface {
-	Put(endpoint production.Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
-}
+type ProductionAPIPutter = production.Putter
 
 // ProductionAPIDeleter defines how the client should perform DELETE calls
-type ProductionAPIDeleter interface {
-	Delete(endpoint production.Endpointer, result interface{}, headers ...func(h *http.Header)) error
-}
+type ProductionAPIDeleter = production.Deleter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
+
+// ProductionAPIContextPoster defines how the client should perform context aware POST calls.
+type ProductionAPIContextPoster = production.ContextPoster
+
+// ProductionAPIContextPutter defines how the client should perform context aware PUT calls.
+type ProductionAPIContextPutter = production.ContextPutter
+
+// ProductionAPIContextDeleter defines how the client should perform context aware DELETE calls.
+type ProductionAPIContextDeleter = production.ContextDeleter
 
 // Creates new DeliveryNumber and returns pointer.
 // Mainly used for storing "new" DeliveryNumbers.
@@ -98,68 +95,107 @@ func New(printOrderId int, deliveryNumber, message string) *DeliveryNumber {
 
 // Returns DeliveryNumber from Publit API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*DeliveryNumber, error) {
-	po := &DeliveryNumber{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, po, queryParams...)
-	return po, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
+}
+
+// Returns DeliveryNumber from Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*DeliveryNumber, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
 // Delivery number list type.
 type DeliveryNumberList []*DeliveryNumber
 
 // Index response object.
-type IndexResponse struct {
-	Count int                `json:"count"`
-	Next  string             `json:"next"`
-	Prev  string             `json:"prev"`
-	Data  DeliveryNumberList `json:"data"`
-}
+type IndexResponse production.IndexResponse[DeliveryNumberList]
 
 // Indexes DeliveryNumbers from the Publit API.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Indexes DeliveryNumbers from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
+
+// Iterates all DeliveryNumbers matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[*DeliveryNumber, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all DeliveryNumbers matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[*DeliveryNumber, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]*DeliveryNumber, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
+	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all DeliveryNumbers matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) (DeliveryNumberList, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all DeliveryNumbers matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) (DeliveryNumberList, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return DeliveryNumberList(l), err
 }
 
 // Updates DeliveryNumber.
 func (d *DeliveryNumber) Update(c ProductionAPIPutter) error {
+	return d.UpdateContext(context.Background(), production.PutterWithContext(c))
+}
+
+// Updates DeliveryNumber.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (d *DeliveryNumber) UpdateContext(ctx context.Context, c ProductionAPIContextPutter) error {
 	if d.ID == 0 {
 		return errors.New("Can not update a non existing number. (ID is missing).")
 	}
 
-	r := Resource{Endpoint: PUT, Id: d.ID}
-	return c.Put(r, d, d)
+	return resource.Update(ctx, c, d.ID, d)
 }
 
 // Stores delivery number.
 func (d *DeliveryNumber) Store(c ProductionAPIPoster) error {
+	return d.StoreContext(context.Background(), production.PosterWithContext(c))
+}
+
+// Stores delivery number.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (d *DeliveryNumber) StoreContext(ctx context.Context, c ProductionAPIContextPoster) error {
 	if d.ID != 0 {
 		return errors.New("Can not create new delivery number for an existing one. (ID is set).")
 	}
-	r := Resource{Endpoint: POST}
-	dnl := &DeliveryNumberList{d}
-	return c.Post(r, d, dnl)
+	return resource.Create(ctx, c, d)
 }
 
 // Deletes delivery number.
 func (d *DeliveryNumber) Delete(c ProductionAPIDeleter) error {
+	return d.DeleteContext(context.Background(), production.DeleterWithContext(c))
+}
+
+// Deletes delivery number.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (d *DeliveryNumber) DeleteContext(ctx context.Context, c ProductionAPIContextDeleter) error {
 	if d.ID == 0 {
 		return errors.New("Can not DELETE a non existing number. (ID is missing).")
 	}
-	r := Resource{Endpoint: DELETE, Id: d.ID}
-	return c.Delete(r, d)
+	return resource.Delete(ctx, c, d.ID, d)
 }
 
 // Method to Resource that fulfils the Endpointer interface as stated in production.
 func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
-
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+	if r.Endpoint == SHOW || r.Endpoint == PUT || r.Endpoint == DELETE {
+		return resource.Endpoint(r.Id).GetEndpoint()
 	}
-
-	return end
+	return resource.Endpoint(0).GetEndpoint()
 }
@@ -0,0 +1,42 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package deliverynumber
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"strconv"
+	"time"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, PRINT_ORDER_ID, DELIVERY_NUMBER, MESSAGE, CREATED_AT, UPDATED_AT,
+}
+
+// QueryBuilder builds query parameters for indexing DeliveryNumbers.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for DeliveryNumbers.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, nil)
+	return q
+}
+
+// Filters on DeliveryNumbers for print order.
+func (q *QueryBuilder) PrintOrderID(id int) *QueryBuilder {
+	return q.Where(PRINT_ORDER_ID, production.OPERATOR_EQUAL, strconv.Itoa(id))
+}
+
+// Filters on DeliveryNumbers created at or after t.
+func (q *QueryBuilder) CreatedAfter(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_GREATER_EQUAL, t)
+}
+
+// Filters on DeliveryNumbers created before t.
+func (q *QueryBuilder) CreatedBefore(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_LESS, t)
+}
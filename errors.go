@@ -0,0 +1,147 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Maximum number of bytes of the response body kept in a ResponseError.
+const RESPONSE_ERROR_BODY_LIMIT = 4096
+
+// Maximum number of bytes of the response body read to decode the Publit error payload of a ResponseError.
+const RESPONSE_ERROR_DECODE_LIMIT = 1 << 20
+
+// Sentinel errors for classifying a ResponseError with errors.Is.
+var (
+	ErrNotFound     = errors.New("Not found")
+	ErrUnauthorized = errors.New("Unauthorized")
+	ErrValidation   = errors.New("Validation failed")
+	ErrRateLimited  = errors.New("Rate limited")
+)
+
+// ResponseError is returned when the Publit production API responds with a non ok status.
+type ResponseError struct {
+	// HTTP status code of the response.
+	StatusCode int
+	// HTTP method of the request.
+	Method string
+	// URL of the request.
+	URL string
+	// Headers of the response.
+	Header http.Header
+	// The first RESPONSE_ERROR_BODY_LIMIT bytes of the response body.
+	Body string
+	// Decoded Publit error payload. Nil if the response did not contain one.
+	APIError *common.APIErrorResponse
+}
+
+// Error method to fulfil the error interface.
+func (e *ResponseError) Error() string {
+	if e.APIError != nil {
+		return e.APIError.GetAsError().Error()
+	}
+
+	// Special message for unauthorized reponse.
+	if e.StatusCode == http.StatusUnauthorized {
+		return fmt.Sprintf(`Unauthorized. Code: "%v"`, e.StatusCode)
+	}
+
+	// Default
+	return fmt.Sprintf(`Response not ok. No information given. Code: "%v"`, e.StatusCode)
+}
+
+// Is method for matching the sentinel errors with errors.Is.
+func (e *ResponseError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity || e.StatusCode == http.StatusBadRequest
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Checks if err is a ResponseError for a resource that could not be found (404).
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Checks if err is a ResponseError for an unauthorized request (401).
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// Checks if err is a ResponseError for a request that failed validation (400 or 422).
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// Checks if err is a ResponseError for a rate limited request (429).
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// Attempts to make a better response error from response.
+// The returned error is always a *ResponseError.
+func MakeResponseError(resp *http.Response) error {
+	e := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			e.URL = resp.Request.URL.String()
+		}
+	}
+
+	if resp.Body == nil {
+		return e
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, RESPONSE_ERROR_DECODE_LIMIT))
+	e.Body = string(body)
+	if len(body) > RESPONSE_ERROR_BODY_LIMIT {
+		e.Body = string(body[:RESPONSE_ERROR_BODY_LIMIT])
+	}
+
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mt == "application/json" {
+		APIErr := &common.APIErrorResponse{}
+		err := json.NewDecoder(bytes.NewReader(body)).Decode(APIErr)
+		if err == nil && APIErr.HasInformation() { // Only keep the API error if it has information.
+			e.APIError = APIErr
+		}
+	}
+
+	return e
+}
+
+// Makes a ResponseError from resp without consuming its body, which can still be read afterwards.
+func peekResponseError(resp *http.Response) *ResponseError {
+	if resp.Body == nil {
+		return MakeResponseError(resp).(*ResponseError)
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, RESPONSE_ERROR_DECODE_LIMIT))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	peek := *resp
+	peek.Body = io.NopCloser(bytes.NewReader(body))
+	return MakeResponseError(&peek).(*ResponseError)
+}
@@ -0,0 +1,120 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseErrorCarriesRequestMetadata(t *testing.T) {
+	t.Parallel()
+	caller := &MockAPICaller{}
+	caller.Response = createCallerResponse(http.StatusUnprocessableEntity, `{"Code":422,"Type":"ValidationError","CombinedInfo":"Some error"}`)
+	caller.Response.Header = http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	i := &struct{}{}
+	err := c.Get(NewEndpoint(), i)
+
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) {
+		t.Fatalf(`Expected a *ResponseError but got: "%T"`, err)
+	}
+
+	if respErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf(`Status code did not match expected. Got: "%d"`, respErr.StatusCode)
+	}
+
+	if respErr.Method != http.MethodGet {
+		t.Errorf(`Method did not match expected. Got: "%s"`, respErr.Method)
+	}
+
+	if respErr.URL != "somebaseurl/production/v2.0/someendpoint" {
+		t.Errorf(`URL did not match expected. Got: "%s"`, respErr.URL)
+	}
+
+	if respErr.APIError == nil || respErr.APIError.Type != "ValidationError" {
+		t.Error("Expected decoded API error payload.")
+	}
+
+	if respErr.Body == "" {
+		t.Error("Expected raw body to be kept.")
+	}
+
+	if !IsValidation(err) {
+		t.Error("Expected error to be classified as validation error.")
+	}
+}
+
+func TestLargeErrorPayloadIsDecoded(t *testing.T) {
+	t.Parallel()
+	infos := strings.Repeat(`{"Info":"The delivery_number field is required.","Type":"ValidationError"},`, 100)
+	body := `{"Errors":[` + strings.TrimSuffix(infos, ",") + `],"Code":422,"Type":"ValidationError","CombinedInfo":"Some error"}`
+	resp := createCallerResponse(http.StatusUnprocessableEntity, body)
+	resp.Header = http.Header{"Content-Type": []string{"application/json"}}
+
+	err := MakeResponseError(resp)
+
+	respErr := err.(*ResponseError)
+	if respErr.APIError == nil || respErr.APIError.CombinedInfo != "Some error" || !IsValidation(err) {
+		t.Errorf(`Expected API error payload over %d bytes to be decoded but got: "%v"`, RESPONSE_ERROR_BODY_LIMIT, err)
+	}
+
+	if len(respErr.Body) != RESPONSE_ERROR_BODY_LIMIT {
+		t.Errorf("Expected body to be cut at %d bytes but got %d.", RESPONSE_ERROR_BODY_LIMIT, len(respErr.Body))
+	}
+}
+
+func TestResponseErrorClassification(t *testing.T) {
+	t.Parallel()
+	table := []struct {
+		StatusCode   int
+		NotFound     bool
+		Unauthorized bool
+		Validation   bool
+		RateLimited  bool
+	}{
+		{http.StatusNotFound, true, false, false, false},
+		{http.StatusUnauthorized, false, true, false, false},
+		{http.StatusUnprocessableEntity, false, false, true, false},
+		{http.StatusBadRequest, false, false, true, false},
+		{http.StatusTooManyRequests, false, false, false, true},
+		{http.StatusInternalServerError, false, false, false, false},
+	}
+
+	for _, v := range table {
+		err := MakeResponseError(createCallerResponse(v.StatusCode, ""))
+
+		// Wrap error to ensure classification works through wrapping.
+		err = fmt.Errorf("Wrapped: %w", err)
+
+		if IsNotFound(err) != v.NotFound {
+			t.Errorf(`IsNotFound did not match expected for code "%d".`, v.StatusCode)
+		}
+		if IsUnauthorized(err) != v.Unauthorized {
+			t.Errorf(`IsUnauthorized did not match expected for code "%d".`, v.StatusCode)
+		}
+		if IsValidation(err) != v.Validation {
+			t.Errorf(`IsValidation did not match expected for code "%d".`, v.StatusCode)
+		}
+		if IsRateLimited(err) != v.RateLimited {
+			t.Errorf(`IsRateLimited did not match expected for code "%d".`, v.StatusCode)
+		}
+	}
+}
+
+// Checks if an error returned from the API is a not found error.
+func ExampleIsNotFound() {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+	}
+
+	err := MakeResponseError(resp)
+
+	fmt.Println(IsNotFound(err))
+	// Output: true
+}
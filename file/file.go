@@ -8,15 +8,16 @@
 package file
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
 )
 
 // File attribute constants.
@@ -43,6 +44,15 @@ const (
 	AUX_PRESIGNED = "presigned_url"
 )
 
+// Span names and attribute keys for traced downloads.
+const (
+	SPAN_DOWNLOAD_FILES  = "file.DownloadFiles"
+	SPAN_DOWNLOAD_FILE   = "file.download"
+	SPAN_ATTR_FILE_COUNT = "publit.file.count"
+	SPAN_ATTR_FILE_ID    = "publit.file.id"
+	SPAN_ATTR_FILE_SIZE  = "publit.file.size"
+)
+
 // Resource struct.
 type Resource struct {
 	Endpoint Endpoint
@@ -58,11 +68,8 @@ const (
 	SHOW
 )
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX: "files",
-	SHOW:  "files/%v",
-}
+// Generic resource backing the package functions.
+var resource = production.NewResource[File, IndexResponse]("files")
 
 // FileList type for handling collections of files.
 type FileList []*File
@@ -85,43 +92,79 @@ type File struct {
 }
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
 
 // Index response object.
-type IndexResponse struct {
-	Count int      `json:"count"`
-	Next  string   `json:"next"`
-	Prev  string   `json:"prev"`
-	Data  FileList `json:"data"`
-}
+type IndexResponse production.IndexResponse[FileList]
 
 // Returns File from Publit API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*File, error) {
-	f := &File{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, f, queryParams...)
-	return f, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
+}
+
+// Returns File from Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*File, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
 // Indexes Files from the Publit API.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Indexes Files from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
+
+// Iterates all Files matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[*File, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all Files matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[*File, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]*File, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
+	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all Files matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) (FileList, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all Files matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) (FileList, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return FileList(l), err
 }
 
 // Retrieves presigned URLs for list of files. Uses worker concurrency pattern.
 // Function returns a map indexed on FileId and any errors if they have occured.
 func (fl FileList) GetPresigned(c ProductionAPIGetter) map[int]error {
+	return fl.GetPresignedContext(context.Background(), production.GetterWithContext(c))
+}
+
+// Retrieves presigned URLs for list of files. Uses worker concurrency pattern.
+// Files not yet handled when ctx is cancelled get the context error in the returned map.
+func (fl FileList) GetPresignedContext(ctx context.Context, c ProductionAPIContextGetter) map[int]error {
 	jobs := make(chan *File, len(fl))
 	results := make(chan FileWorkerError, len(fl))
 
 	// Create workers.
 	for i := 0; i < workerAmount; i++ {
-		go presignedWorker(c, jobs, results)
+		go presignedWorker(ctx, c, jobs, results)
 	}
 
 	// Add jobs to channel.
@@ -151,9 +194,12 @@ type FileWorkerError struct {
 }
 
 // Worker designated for running presigned url queries.
-func presignedWorker(c ProductionAPIGetter, files <-chan *File, results chan<- FileWorkerError) {
+func presignedWorker(ctx context.Context, c ProductionAPIContextGetter, files <-chan *File, results chan<- FileWorkerError) {
 	for f := range files {
-		err := f.GetPresignedUrl(c)
+		err := ctx.Err()
+		if err == nil {
+			err = f.GetPresignedUrlContext(ctx, c)
+		}
 		fw := FileWorkerError{
 			Error:  err,
 			FileId: f.ID,
@@ -165,8 +211,14 @@ func presignedWorker(c ProductionAPIGetter, files <-chan *File, results chan<- F
 // Retrieves presigned url for file.
 // The presigned url is a download url valid for a certain amount of time.
 func (f *File) GetPresignedUrl(c ProductionAPIGetter) error {
-	r := Resource{Endpoint: SHOW, Id: f.ID}
-	err := c.Get(r, f, GetPresignedAuxParamFunc())
+	return f.GetPresignedUrlContext(context.Background(), production.GetterWithContext(c))
+}
+
+// Retrieves presigned url for file.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (f *File) GetPresignedUrlContext(ctx context.Context, c ProductionAPIContextGetter) error {
+	err := c.GetContext(ctx, resource.Endpoint(f.ID), f, GetPresignedAuxParamFunc())
+	production.MetricsOf(c).IncPresignedFetch(err)
 	return err
 }
 
@@ -177,14 +229,10 @@ func GetPresignedAuxParamFunc() func(q url.Values) {
 
 // Method to Resource that fullfils the Enpointer interface as stated in production.
 func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
-
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+	if r.Endpoint == SHOW {
+		return resource.Endpoint(r.Id).GetEndpoint()
 	}
-
-	return end
+	return resource.Endpoint(0).GetEndpoint()
 }
 
 // Sets number of workers for methods using concurrent workers.
@@ -194,7 +242,35 @@ func SetWorkerAmount(amount int) {
 
 // Downloads file from FileList.
 // Return map of errors indexed on fileID and potential error generated by the method.
+// Files are downloaded through PlainGetter.
 func (fl FileList) DownloadFiles(c ProductionAPIGetter, outDir string) (map[int]error, error) {
+	get := func(ctx context.Context, url string) (*http.Response, error) {
+		return PlainGetter(url)
+	}
+	return fl.downloadFiles(context.Background(), production.GetterWithContext(c), outDir, get)
+}
+
+// Downloads file from FileList.
+// Cancelling ctx aborts ongoing downloads and any files not yet downloaded get the context error in the returned map.
+// If c traces its calls each download gets its own span, as a child of a span covering all downloads.
+// Files are downloaded through PlainContextGetter.
+func (fl FileList) DownloadFilesContext(ctx context.Context, c ProductionAPIContextGetter, outDir string) (map[int]error, error) {
+	get := func(ctx context.Context, url string) (*http.Response, error) {
+		return PlainContextGetter(ctx, url)
+	}
+	return fl.downloadFiles(ctx, c, outDir, get)
+}
+
+// Downloads files from FileList through get.
+func (fl FileList) downloadFiles(ctx context.Context, c ProductionAPIContextGetter, outDir string, get plainGetFunc) (map[int]error, error) {
+	tracer := production.TracerOf(c)
+	if tracer != nil {
+		var span production.Span
+		ctx, span = tracer.Start(ctx, SPAN_DOWNLOAD_FILES)
+		span.SetAttribute(SPAN_ATTR_FILE_COUNT, len(fl))
+		defer span.End()
+	}
+
 	errs := make(map[int]error, len(fl))
 	if stat, err := os.Stat(outDir); err != nil || !stat.IsDir() {
 		return errs, errors.New("Output dir is not a directory.")
@@ -214,7 +290,7 @@ func (fl FileList) DownloadFiles(c ProductionAPIGetter, outDir string) (map[int]
 
 	// Get presigned for files without presigned urls.
 	if len(noPresigned) > 0 {
-		preErrs := noPresigned.GetPresigned(c)
+		preErrs := noPresigned.GetPresignedContext(ctx, c)
 		// Range error list and see if any errors were returned.
 		// Also "bake" the returned map into the map of this method to make sure map conforms to init length of fl.
 		presignedError := false
@@ -231,7 +307,7 @@ func (fl FileList) DownloadFiles(c ProductionAPIGetter, outDir string) (map[int]
 
 	// Create workers.
 	for i := 0; i < workerAmount; i++ {
-		go downloadWorker(outDir, jobs, results)
+		go downloadWorker(ctx, tracer, production.MetricsOf(c), get, outDir, jobs, results)
 	}
 
 	// Range files and create a download job for each file.
@@ -250,44 +326,97 @@ func (fl FileList) DownloadFiles(c ProductionAPIGetter, outDir string) (map[int]
 }
 
 // Plain getter method. Performs plain GET requests for file download from URL.
-// Made as a variable for aiding testing.
+// Made as a variable for aiding testing. Used by DownloadFiles.
 var PlainGetter func(url string) (*http.Response, error) = func(url string) (*http.Response, error) {
 	return http.Get(url)
 }
 
+// Plain context aware getter method. Performs plain GET requests for file download from URL.
+// Made as a variable for aiding testing. Used by DownloadFilesContext.
+var PlainContextGetter func(ctx context.Context, url string) (*http.Response, error) = func(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
+// Performs plain GET request for file download from URL.
+type plainGetFunc func(ctx context.Context, url string) (*http.Response, error)
+
 // Download worker.
-func downloadWorker(outDir string, files <-chan *File, results chan<- FileWorkerError) {
+func downloadWorker(ctx context.Context, tracer production.Tracer, metrics production.Metrics, get plainGetFunc, outDir string, files <-chan *File, results chan<- FileWorkerError) {
 	for f := range files {
-		fw := FileWorkerError{
-			FileId: f.ID,
+		n, err := tracedDownloadFile(ctx, tracer, get, outDir, f)
+
+		metrics.AddDownloadBytes(n)
+		if err != nil {
+			metrics.IncDownloadFailure()
 		}
 
-		resp, err := PlainGetter(f.Presigned)
-		if resp.StatusCode != http.StatusOK {
-			fw.Error = errors.New(fmt.Sprintf(`Could not download file. Server responded with code: "%s"`, resp.StatusCode))
-			results <- fw
-			continue
+		results <- FileWorkerError{
+			FileId: f.ID,
+			Error:  err,
 		}
+	}
+}
+
+// Downloads a single file with presigned url to outDir in a span of its own, if tracer is set.
+func tracedDownloadFile(ctx context.Context, tracer production.Tracer, get plainGetFunc, outDir string, f *File) (int64, error) {
+	if tracer == nil {
+		return downloadFile(ctx, get, outDir, f)
+	}
+
+	ctx, span := tracer.Start(ctx, SPAN_DOWNLOAD_FILE)
+	defer span.End()
 
+	span.SetAttribute(SPAN_ATTR_FILE_ID, f.ID)
+	span.SetAttribute(SPAN_ATTR_FILE_SIZE, f.Size)
+
+	n, err := downloadFile(ctx, get, outDir, f)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return n, err
+}
+
+// Downloads a single file with presigned url to outDir. Returns the number of bytes written.
+func downloadFile(ctx context.Context, get plainGetFunc, outDir string, f *File) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	resp, err := get(ctx, f.Presigned)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Body != nil {
 		defer resp.Body.Close()
-		if err != nil {
-			fw.Error = err
-			results <- fw
-			continue
-		}
-		f, err := os.Create(outDir + "/" + f.OriginalName)
-		defer f.Close()
-		if err != nil {
-			fw.Error = err
-			results <- fw
-			continue
-		}
-		_, err = io.Copy(f, resp.Body)
-		if err != nil {
-			fw.Error = err
-			results <- fw
-			continue
-		}
-		results <- fw
 	}
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, errors.New(fmt.Sprintf(`Could not download file. Server responded with code: "%d"`, resp.StatusCode))
+	}
+
+	out, err := os.Create(outDir + "/" + f.OriginalName)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	return io.Copy(out, contextReader{ctx: ctx, r: resp.Body})
+}
+
+// Reader that stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read method to fulfil the io.Reader interface.
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
 }
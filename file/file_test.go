@@ -2,6 +2,7 @@ package file
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
@@ -10,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"github.com/publitsweden/APIUtilityGoSDK/client"
 	"fmt"
@@ -50,6 +52,30 @@ func TestCanIndexFiles(t *testing.T) {
 	}
 }
 
+func TestIndexAllReturnsFileList(t *testing.T) {
+	t.Parallel()
+
+	cb := func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
+		ir := model.(*IndexResponse)
+		ir.Data = FileList{&File{ID: 1}, &File{ID: 2}}
+	}
+
+	c := &MockProductionAPIClient{
+		T:       t,
+		GetCall: cb,
+	}
+
+	fl, err := IndexAll(c, 0)
+
+	if err != nil {
+		t.Error("Got Error but did not expect one", err)
+	}
+
+	if len(fl) != 2 {
+		t.Errorf("Expected 2 files but got %d.", len(fl))
+	}
+}
+
 func TestCanShowFile(t *testing.T) {
 	t.Parallel()
 	id := 5
@@ -226,6 +252,34 @@ func TestWorkerCanFetchPresignedForFileList(t *testing.T) {
 	}
 }
 
+func TestGetPresignedStopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+	fl := FileList{
+		&File{ID: 1},
+		&File{ID: 2},
+	}
+
+	cb := func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
+		t.Error("Get was called even though context was cancelled.")
+	}
+
+	c := &MockProductionAPIClient{
+		T:       t,
+		GetCall: cb,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errorMap := fl.GetPresignedContext(ctx, production.GetterWithContext(c))
+
+	for _, f := range fl {
+		if errorMap[f.ID] != context.Canceled {
+			t.Errorf(`Expected context.Canceled for file "%d" but got: "%v"`, f.ID, errorMap[f.ID])
+		}
+	}
+}
+
 func TestCanDownloadFilesFromList(t *testing.T) {
 	urlList := []struct {
 		Url  string
@@ -417,6 +471,70 @@ func TestDownloadFileReturnsErrorIfFileCouldNotBeDownloadedDueToBadStatus(t *tes
 	}
 }
 
+func TestDownloadFilesContextUsesPlainContextGetter(t *testing.T) {
+	defaultGetter := PlainContextGetter
+	defer func() { PlainContextGetter = defaultGetter }()
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "value")
+
+	called := false
+	PlainContextGetter = func(c context.Context, url string) (*http.Response, error) {
+		called = c.Value(key{}) == "value"
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewBufferString("body"))}, nil
+	}
+
+	outdir, err := ioutil.TempDir("", "outputdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outdir)
+
+	fl := FileList{&File{ID: 1, OriginalName: "somefile1.txt", Presigned: "some/url/to/presigned"}}
+	errs, err := fl.DownloadFilesContext(ctx, production.GetterWithContext(&MockProductionAPIClient{}), outdir)
+
+	if err != nil || errs[1] != nil {
+		t.Error("Received an error but was not expecting one.", err, errs[1])
+	}
+	if !called {
+		t.Error("Expected download to be made through PlainContextGetter with the context.")
+	}
+	if PlainGetter == nil {
+		t.Error("Expected PlainGetter to have a default.")
+	}
+}
+
+func TestDownloadFilesAreTraced(t *testing.T) {
+	t.Parallel()
+	fl := FileList{
+		&File{ID: 1, OriginalName: "somefile1.txt", Presigned: "some/url/to/presigned"},
+		&File{ID: 2, OriginalName: "somefile2.txt", Presigned: "some/url/to/presigned"},
+	}
+
+	outdir, err := ioutil.TempDir("", "outputdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outdir)
+
+	// Cancel the context to not depend on PlainContextGetter, spans are recorded either way.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tracer := &RecordingTracer{}
+	c := &TracingMockProductionAPIClient{Tracer: tracer}
+	fl.DownloadFilesContext(ctx, production.GetterWithContext(c), outdir)
+
+	names := map[string]int{}
+	for _, v := range tracer.Spans {
+		names[v]++
+	}
+
+	if names[SPAN_DOWNLOAD_FILES] != 1 || names[SPAN_DOWNLOAD_FILE] != 2 {
+		t.Errorf(`Expected one span for all downloads and one per file, got: "%v"`, names)
+	}
+}
+
 func TestDownloadFilesReturnsErrorIfDirectoryDoesNotExists(t *testing.T) {
 	t.Parallel()
 	unexistingDir := "some/dir/that/doesnt/exist"
@@ -450,6 +568,38 @@ func (c *MockProductionAPIClient) Get(endpoint production.Endpointer, model inte
 	return nil
 }
 
+// Test helper Client Mock with a Tracer.
+type TracingMockProductionAPIClient struct {
+	MockProductionAPIClient
+	Tracer production.Tracer
+}
+
+func (c *TracingMockProductionAPIClient) CallTracer() production.Tracer {
+	return c.Tracer
+}
+
+// Test helper Tracer recording span names.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	Spans []string
+}
+
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, production.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Spans = append(t.Spans, name)
+	return ctx, noopSpan{}
+}
+
+func (t *RecordingTracer) Inject(ctx context.Context, h http.Header) {}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) SetErrorStatus(description string)          {}
+func (noopSpan) End()                                       {}
+
 // Examples
 
 func ExampleShow() {
@@ -0,0 +1,42 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package file
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"time"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, TYPE, ORIGINAL_NAME, SIZE, EXTENSION, MIME, CHECKSUM, URL, AUTO_GENERATED,
+	CREATED_AT, UPDATED_AT, DELETED_AT,
+}
+
+// QueryBuilder builds query parameters for indexing Files.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for Files.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, nil)
+	return q
+}
+
+// Filters on Files of type.
+func (q *QueryBuilder) Type(fileType string) *QueryBuilder {
+	return q.Where(TYPE, production.OPERATOR_EQUAL, fileType)
+}
+
+// Filters on Files created at or after t.
+func (q *QueryBuilder) CreatedAfter(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_GREATER_EQUAL, t)
+}
+
+// Filters on Files created before t.
+func (q *QueryBuilder) CreatedBefore(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_LESS, t)
+}
@@ -0,0 +1,178 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Endpoint used to check that authenticated calls work. Listing a single country is cheap and needs no permissions beyond a valid token.
+const HEALTH_CHECK_ENDPOINT = "countries"
+
+// Failure kinds of a HealthProbe.
+const (
+	HEALTH_FAILURE_DNS          = "dns"
+	HEALTH_FAILURE_TLS          = "tls"
+	HEALTH_FAILURE_CONNECTION   = "connection"
+	HEALTH_FAILURE_TIMEOUT      = "timeout"
+	HEALTH_FAILURE_CIRCUIT_OPEN = "circuit_open"
+	HEALTH_FAILURE_UNAUTHORIZED = "unauthorized"
+	HEALTH_FAILURE_STATUS       = "status"
+	HEALTH_FAILURE_OTHER        = "other"
+)
+
+// HealthReport is the outcome of APIClient.HealthCheck.
+type HealthReport struct {
+	// Set if the service is up and authenticated calls work.
+	Healthy   bool      `json:"healthy"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Unauthenticated call to the status check.
+	StatusCheck HealthProbe `json:"status_check"`
+	// Authenticated call listing a single country.
+	Authenticated HealthProbe `json:"authenticated"`
+	// Set if the current API token was accepted without being refreshed.
+	TokenValid bool `json:"token_valid"`
+	// Set if the API token had to be refreshed for the authenticated call to work.
+	TokenRefreshed bool `json:"token_refreshed"`
+	// Server clock minus local clock, from the Date header of the status check. Accurate to about a second.
+	ClockSkew time.Duration `json:"clock_skew_ns"`
+}
+
+// HealthProbe is the outcome of a single call made by APIClient.HealthCheck.
+type HealthProbe struct {
+	OK         bool          `json:"ok"`
+	Latency    time.Duration `json:"latency_ns"`
+	StatusCode int           `json:"status_code,omitempty"`
+	// Kind of failure, one of the HEALTH_FAILURE constants. Empty if OK.
+	Failure string `json:"failure,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthCheck checks the status check, whether authenticated calls work and the server clock.
+// Unlike StatusCheck it tells why the API could not be reached, e.g. DNS, TLS or authentication failures.
+func (c *APIClient) HealthCheck(ctx context.Context) HealthReport {
+	report := HealthReport{CheckedAt: time.Now()}
+
+	report.StatusCheck, report.ClockSkew = c.probeStatusCheck(ctx)
+
+	// Probes always call the API, as a cached response would hide an expired token.
+	uncached := *c
+	uncached.Cache = nil
+
+	// Try the current token first, so that an expired token is reported even if it can be refreshed.
+	noRefresh := uncached
+	noRefresh.DisableTokenRefresh = true
+	report.Authenticated = noRefresh.probeAuthenticated(ctx)
+	report.TokenValid = report.Authenticated.OK
+
+	if report.Authenticated.Failure == HEALTH_FAILURE_UNAUTHORIZED && !c.DisableTokenRefresh {
+		report.Authenticated = uncached.probeAuthenticated(ctx)
+		report.TokenRefreshed = report.Authenticated.OK
+	}
+
+	report.Healthy = report.StatusCheck.OK && report.Authenticated.OK
+	return report
+}
+
+// HealthHandler returns an http.Handler running HealthCheck, e.g. for readiness probes.
+// It responds with the HealthReport as JSON, with status 200 OK if healthy and 503 Service Unavailable otherwise.
+func HealthHandler(c *APIClient) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.HealthCheck(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Healthy {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// Calls the status check. Returns the probe and the clock skew of the server.
+func (c *APIClient) probeStatusCheck(ctx context.Context) (HealthProbe, time.Duration) {
+	start := time.Now()
+	resp, err := c.statusCheck(ctx)
+	p := HealthProbe{Latency: time.Since(start)}
+
+	if err != nil {
+		p.Failure, p.Error = classifyHealthError(err), err.Error()
+		return p, 0
+	}
+	discardResponse(resp)
+
+	p.StatusCode = resp.StatusCode
+	p.OK = resp.StatusCode == http.StatusOK
+	if !p.OK {
+		p.Failure = HEALTH_FAILURE_STATUS
+	}
+
+	var skew time.Duration
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		// Compare against the middle of the call, when the server most likely set the header.
+		skew = date.Sub(start.Add(p.Latency / 2)).Round(time.Second)
+	}
+
+	return p, skew
+}
+
+// Lists a single country to check that authenticated calls work.
+func (c APIClient) probeAuthenticated(ctx context.Context) HealthProbe {
+	start := time.Now()
+	err := c.GetContext(ctx, ResourceEndpoint{Path: HEALTH_CHECK_ENDPOINT}, &struct{}{}, common.QueryLimit(1, 0))
+	p := HealthProbe{Latency: time.Since(start), OK: err == nil}
+
+	if err == nil {
+		p.StatusCode = http.StatusOK
+		return p
+	}
+
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		p.StatusCode = respErr.StatusCode
+	}
+	p.Failure, p.Error = classifyHealthError(err), Redact(err.Error())
+	return p
+}
+
+// Classifies err as one of the HEALTH_FAILURE constants.
+func classifyHealthError(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var respErr *ResponseError
+	var netErr net.Error
+	var opErr *net.OpError
+
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return HEALTH_FAILURE_CIRCUIT_OPEN
+	case errors.Is(err, ErrUnauthorized):
+		return HEALTH_FAILURE_UNAUTHORIZED
+	case errors.As(err, &respErr):
+		return HEALTH_FAILURE_STATUS
+	case errors.As(err, &dnsErr):
+		return HEALTH_FAILURE_DNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &authorityErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return HEALTH_FAILURE_TLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return HEALTH_FAILURE_TIMEOUT
+	case errors.As(err, &opErr):
+		return HEALTH_FAILURE_CONNECTION
+	}
+	return HEALTH_FAILURE_OTHER
+}
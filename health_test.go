@@ -0,0 +1,179 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"encoding/json"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckReportsHealthyAPI(t *testing.T) {
+	t.Parallel()
+	caller := &HealthAPICaller{Date: time.Now().Add(time.Hour)}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	r := c.HealthCheck(context.Background())
+
+	if !r.Healthy || !r.StatusCheck.OK || !r.Authenticated.OK || !r.TokenValid {
+		t.Errorf("Expected healthy report but got: %+v", r)
+	}
+	if r.StatusCheck.StatusCode != http.StatusOK {
+		t.Errorf("Expected status check status code 200 but got %d.", r.StatusCheck.StatusCode)
+	}
+	if r.ClockSkew < 59*time.Minute || r.ClockSkew > 61*time.Minute {
+		t.Errorf("Expected clock skew of an hour but got %v.", r.ClockSkew)
+	}
+	if !strings.Contains(caller.AuthQuery, "limit=1") {
+		t.Errorf(`Expected authenticated call to be limited but got query: "%s"`, caller.AuthQuery)
+	}
+}
+
+func TestHealthCheckReportsRefreshedToken(t *testing.T) {
+	t.Parallel()
+	caller := &HealthAPICaller{TokenExpired: true}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	r := c.HealthCheck(context.Background())
+
+	if !r.Healthy || r.TokenValid || !r.TokenRefreshed {
+		t.Errorf("Expected healthy report with refreshed token but got: %+v", r)
+	}
+	if caller.Refreshes != 1 {
+		t.Errorf("Expected token to be refreshed once but got %d.", caller.Refreshes)
+	}
+}
+
+func TestHealthCheckReportsUnauthorized(t *testing.T) {
+	t.Parallel()
+	caller := &HealthAPICaller{TokenExpired: true, RefreshFails: true}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	r := c.HealthCheck(context.Background())
+
+	if r.Healthy || !r.StatusCheck.OK || r.Authenticated.OK {
+		t.Errorf("Expected unhealthy report with working status check but got: %+v", r)
+	}
+	if r.Authenticated.Failure != HEALTH_FAILURE_UNAUTHORIZED || r.Authenticated.StatusCode != http.StatusUnauthorized {
+		t.Errorf(`Expected unauthorized failure but got: "%s", %d`, r.Authenticated.Failure, r.Authenticated.StatusCode)
+	}
+}
+
+func TestHealthCheckIsNotServedFromCache(t *testing.T) {
+	t.Parallel()
+	caller := &HealthAPICaller{}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Cache: DefaultCache(NewMemoryCache(10))}
+
+	// Cache the authenticated health check call.
+	if err := c.Get(ResourceEndpoint{Path: HEALTH_CHECK_ENDPOINT}, &struct{}{}, common.QueryLimit(1, 0)); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	caller.TokenExpired, caller.RefreshFails = true, true
+	r := c.HealthCheck(context.Background())
+
+	if r.Healthy || r.TokenValid || r.Authenticated.Failure != HEALTH_FAILURE_UNAUTHORIZED {
+		t.Errorf("Expected unhealthy report with expired token but got: %+v", r)
+	}
+}
+
+func TestHealthCheckClassifiesDNSFailure(t *testing.T) {
+	t.Parallel()
+	caller := &HealthAPICaller{Err: &net.DNSError{Err: "no such host", Name: "url.to.publit", IsNotFound: true}}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	r := c.HealthCheck(context.Background())
+
+	if r.Healthy || r.StatusCheck.OK {
+		t.Errorf("Expected unhealthy report but got: %+v", r)
+	}
+	if r.StatusCheck.Failure != HEALTH_FAILURE_DNS || r.Authenticated.Failure != HEALTH_FAILURE_DNS {
+		t.Errorf(`Expected DNS failures but got: "%s", "%s"`, r.StatusCheck.Failure, r.Authenticated.Failure)
+	}
+	if !strings.Contains(r.StatusCheck.Error, "no such host") {
+		t.Errorf(`Expected error to be reported but got: "%s"`, r.StatusCheck.Error)
+	}
+}
+
+func TestHealthHandlerRespondsWithReport(t *testing.T) {
+	t.Parallel()
+	tests := map[int]int{
+		http.StatusOK:                 http.StatusOK,
+		http.StatusServiceUnavailable: http.StatusInternalServerError,
+	}
+
+	for expected, status := range tests {
+		c := &APIClient{Client: &HealthAPICaller{AuthStatus: status}, BaseUrl: "somebaseurl"}
+		w := httptest.NewRecorder()
+
+		HealthHandler(c).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+		if w.Code != expected {
+			t.Errorf("Expected status %d but got %d.", expected, w.Code)
+		}
+
+		r := HealthReport{}
+		if err := json.Unmarshal(w.Body.Bytes(), &r); err != nil {
+			t.Fatal("Got error but was not expecting one.", err)
+		}
+		if r.Healthy != (expected == http.StatusOK) {
+			t.Errorf("Expected reported health to match status %d but got: %+v", expected, r)
+		}
+	}
+}
+
+// APICaller mock serving the status check and the authenticated health check call.
+type HealthAPICaller struct {
+	// Error returned for all calls.
+	Err error
+	// Date of status check responses.
+	Date time.Time
+	// Status of authenticated calls. Defaults to 200 OK.
+	AuthStatus int
+	// Authenticated calls are unauthorized until the token is refreshed.
+	TokenExpired bool
+	// Token refreshes succeed but the token stays expired.
+	RefreshFails bool
+	AuthQuery    string
+	Refreshes    int
+}
+
+func (c *HealthAPICaller) Call(r *http.Request) (*http.Response, error) {
+	if c.Err != nil {
+		return nil, c.Err
+	}
+
+	if strings.HasSuffix(r.URL.Path, RESOURCE_STATUSCHECK) {
+		resp := createCallerResponse(http.StatusOK, `{}`)
+		if !c.Date.IsZero() {
+			resp.Header = http.Header{"Date": {c.Date.UTC().Format(http.TimeFormat)}}
+		}
+		return resp, nil
+	}
+
+	c.AuthQuery = r.URL.RawQuery
+	status := c.AuthStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if c.TokenExpired {
+		status = http.StatusUnauthorized
+	}
+
+	return createCallerResponse(status, `{"data":[]}`), nil
+}
+
+func (c *HealthAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *HealthAPICaller) SetNewAPIToken(r *http.Request) error {
+	c.Refreshes++
+	c.TokenExpired = c.RefreshFails
+	return nil
+}
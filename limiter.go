@@ -0,0 +1,222 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter controls the rate and concurrency of calls made by an APIClient.
+// Share a single Limiter between clients to limit their combined load on the Publit API.
+type Limiter interface {
+	// Acquire blocks until a call may be made or ctx is done.
+	// The returned release function must be called with the outcome of the call once it completes.
+	Acquire(ctx context.Context) (release func(resp *http.Response, err error), err error)
+}
+
+// RateLimit configures a RateLimiter.
+type RateLimit struct {
+	// Sustained number of requests per second. Zero means no rate limit.
+	RequestsPerSecond float64
+	// Number of requests that may be made in a burst above the sustained rate. At least one.
+	Burst int
+	// Maximum number of requests in flight at the same time. Zero means no limit.
+	MaxInFlight int
+	// Factor the rate is multiplied with when the API responds with 429 Too Many Requests. Defaults to 0.5.
+	SlowdownFactor float64
+	// Lowest rate the limiter slows down to. Defaults to a tenth of RequestsPerSecond.
+	MinRequestsPerSecond float64
+}
+
+// LimiterStats holds metrics of a RateLimiter.
+type LimiterStats struct {
+	// Number of acquired calls.
+	Calls int64
+	// Number of calls that had to wait.
+	Waits int64
+	// Total time spent waiting.
+	TotalWait time.Duration
+	// Longest single wait.
+	MaxWait time.Duration
+	// Number of 429 responses seen.
+	Throttled int64
+	// Current number of requests in flight.
+	InFlight int
+	// Current rate in requests per second, after any adaptive slowdown.
+	RequestsPerSecond float64
+}
+
+// RateLimiter is a token bucket Limiter with a cap on requests in flight.
+// It slows down when the API responds with 429 Too Many Requests, pausing for any Retry-After given,
+// and gradually recovers to the configured rate on successful responses.
+type RateLimiter struct {
+	mu          sync.Mutex
+	config      RateLimit
+	rate        float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	sem         chan struct{}
+	stats       LimiterStats
+}
+
+// Creates new RateLimiter.
+func NewRateLimiter(config RateLimit) *RateLimiter {
+	if config.Burst < 1 {
+		config.Burst = 1
+	}
+
+	if config.SlowdownFactor <= 0 || config.SlowdownFactor >= 1 {
+		config.SlowdownFactor = 0.5
+	}
+
+	if config.MinRequestsPerSecond <= 0 {
+		config.MinRequestsPerSecond = config.RequestsPerSecond / 10
+	}
+
+	l := &RateLimiter{
+		config: config,
+		rate:   config.RequestsPerSecond,
+		tokens: float64(config.Burst),
+		last:   time.Now(),
+	}
+
+	if config.MaxInFlight > 0 {
+		l.sem = make(chan struct{}, config.MaxInFlight)
+	}
+
+	return l
+}
+
+// Acquire method to fulfil the Limiter interface.
+func (l *RateLimiter) Acquire(ctx context.Context) (func(resp *http.Response, err error), error) {
+	start := time.Now()
+
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	for {
+		wait := l.reserve()
+		if wait <= 0 {
+			break
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			if l.sem != nil {
+				<-l.sem
+			}
+			return nil, err
+		}
+	}
+
+	l.record(time.Since(start))
+
+	return l.release, nil
+}
+
+// Returns current metrics of the limiter.
+func (l *RateLimiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := l.stats
+	s.InFlight = len(l.sem)
+	s.RequestsPerSecond = l.rate
+	return s
+}
+
+// Takes a token from the bucket. Returns how long to wait before trying again if none was available.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > float64(l.config.Burst) {
+		l.tokens = float64(l.config.Burst)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Records an acquired call and the time spent waiting for it.
+func (l *RateLimiter) record(wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Calls++
+
+	// Ignore the time it takes to pass through the limiter without waiting.
+	if wait < time.Millisecond {
+		return
+	}
+
+	l.stats.Waits++
+	l.stats.TotalWait += wait
+	if wait > l.stats.MaxWait {
+		l.stats.MaxWait = wait
+	}
+}
+
+// Releases a call, adapting the rate to the response.
+func (l *RateLimiter) release(resp *http.Response, err error) {
+	if l.sem != nil {
+		<-l.sem
+	}
+
+	if err != nil || resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.stats.Throttled++
+
+		if l.rate > 0 {
+			l.rate *= l.config.SlowdownFactor
+			if l.rate < l.config.MinRequestsPerSecond {
+				l.rate = l.config.MinRequestsPerSecond
+			}
+		}
+
+		if ra, ok := retryAfter(resp); ok {
+			if until := time.Now().Add(ra); until.After(l.pausedUntil) {
+				l.pausedUntil = until
+			}
+		}
+		return
+	}
+
+	// Recover towards the configured rate by a twentieth of it for each successful response.
+	if resp.StatusCode < http.StatusInternalServerError && l.rate < l.config.RequestsPerSecond {
+		l.rate += l.config.RequestsPerSecond / 20
+		if l.rate > l.config.RequestsPerSecond {
+			l.rate = l.config.RequestsPerSecond
+		}
+	}
+}
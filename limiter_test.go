@@ -0,0 +1,145 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterLimitsRate(t *testing.T) {
+	t.Parallel()
+	l := NewRateLimiter(RateLimit{RequestsPerSecond: 50, Burst: 1})
+	c := &APIClient{Client: &ConcurrencyAPICaller{}, BaseUrl: "somebaseurl", Limiter: l}
+
+	start := time.Now()
+	for n := 0; n < 4; n++ {
+		if err := c.Get(NewEndpoint(), &struct{}{}); err != nil {
+			t.Fatal("Got error but was not expecting one.", err)
+		}
+	}
+
+	// First call uses the burst, the remaining three wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 55*time.Millisecond {
+		t.Errorf("Expected calls to be limited but they took %v.", elapsed)
+	}
+
+	s := l.Stats()
+	if s.Calls != 4 {
+		t.Errorf("Expected 4 calls in stats but got %d.", s.Calls)
+	}
+	if s.Waits == 0 || s.TotalWait == 0 {
+		t.Error("Expected wait time to be recorded in stats.")
+	}
+}
+
+func TestRateLimiterCapsRequestsInFlight(t *testing.T) {
+	t.Parallel()
+	caller := &ConcurrencyAPICaller{Delay: 10 * time.Millisecond}
+	l := NewRateLimiter(RateLimit{MaxInFlight: 2})
+	c := APIClient{Client: caller, BaseUrl: "somebaseurl", Limiter: l}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Get(NewEndpoint(), &struct{}{}); err != nil {
+				t.Error("Got error but was not expecting one.", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := caller.MaxInFlight(); max > 2 {
+		t.Errorf("Expected at most 2 requests in flight but got %d.", max)
+	}
+
+	if s := l.Stats(); s.InFlight != 0 {
+		t.Errorf("Expected no requests in flight after calls but got %d.", s.InFlight)
+	}
+}
+
+func TestRateLimiterSlowsDownOnTooManyRequests(t *testing.T) {
+	t.Parallel()
+	l := NewRateLimiter(RateLimit{RequestsPerSecond: 100, Burst: 10})
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	release(createCallerResponse(http.StatusTooManyRequests, ""), nil)
+
+	s := l.Stats()
+	if s.RequestsPerSecond != 50 {
+		t.Errorf("Expected rate to be halved to 50 but got %v.", s.RequestsPerSecond)
+	}
+	if s.Throttled != 1 {
+		t.Errorf("Expected 1 throttled response but got %d.", s.Throttled)
+	}
+
+	release, err = l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	release(createCallerResponse(http.StatusOK, ""), nil)
+
+	if s := l.Stats(); s.RequestsPerSecond <= 50 {
+		t.Errorf("Expected rate to recover after success but got %v.", s.RequestsPerSecond)
+	}
+}
+
+func TestRateLimiterPausesOnRetryAfter(t *testing.T) {
+	t.Parallel()
+	l := NewRateLimiter(RateLimit{RequestsPerSecond: 100, Burst: 10})
+
+	release, _ := l.Acquire(context.Background())
+	resp := createCallerResponse(http.StatusTooManyRequests, "")
+	resp.Header = http.Header{"Retry-After": []string{"1"}}
+	release(resp, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf(`Expected acquire to wait for Retry-After and time out, got: "%v"`, err)
+	}
+}
+
+// APICaller mock recording the highest number of concurrent calls.
+type ConcurrencyAPICaller struct {
+	Delay time.Duration
+
+	inflight int32
+	max      int32
+}
+
+func (c *ConcurrencyAPICaller) Call(r *http.Request) (*http.Response, error) {
+	n := atomic.AddInt32(&c.inflight, 1)
+	defer atomic.AddInt32(&c.inflight, -1)
+
+	for {
+		max := atomic.LoadInt32(&c.max)
+		if n <= max || atomic.CompareAndSwapInt32(&c.max, max, n) {
+			break
+		}
+	}
+
+	time.Sleep(c.Delay)
+	return createCallerResponse(http.StatusOK, `{}`), nil
+}
+
+func (c *ConcurrencyAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *ConcurrencyAPICaller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
+
+func (c *ConcurrencyAPICaller) MaxInFlight() int {
+	return int(atomic.LoadInt32(&c.max))
+}
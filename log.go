@@ -0,0 +1,212 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Maximum number of bytes of a body read for logging. Longer bodies are redacted and logged up to this limit.
+const LOG_BODY_READ_LIMIT = 1 << 20
+
+// Message of log records for API calls.
+const LOG_MESSAGE = "Production API call"
+
+// Logging configures logging of the calls made by an APIClient.
+// One record is logged per call, after any retries and token refresh.
+//
+// Authorization headers, presigned URL signatures and fields registered with RegisterRedactedFields are redacted.
+type Logging struct {
+	Logger *slog.Logger
+	// Level of successful calls.
+	Level slog.Level
+	// Level of failed calls, i.e. errors and responses other than 200 OK and 304 Not Modified.
+	ErrorLevel slog.Level
+	// Maximum number of bytes of request and response bodies logged. Bodies are not logged if zero.
+	BodyLimit int
+}
+
+// Returns Logging logging successful calls at debug level and failed calls at warning level, including the first
+// kilobyte of bodies.
+func DefaultLogging(logger *slog.Logger) *Logging {
+	return &Logging{
+		Logger:     logger,
+		Level:      slog.LevelDebug,
+		ErrorLevel: slog.LevelWarn,
+		BodyLimit:  1024,
+	}
+}
+
+// Query parameters of URLs carrying presigned URL signatures and credentials.
+var signatureParams = regexp.MustCompile(`(?i)((?:X-Amz-Signature|X-Amz-Credential|X-Amz-Security-Token|Signature|Sig)=)[^&"\s\\]+`)
+
+// JSON fields redacted from logged bodies.
+var redactedFields = &fieldRedactor{}
+
+// Redacts the string values of JSON fields.
+type fieldRedactor struct {
+	mu     sync.RWMutex
+	fields []string
+	re     *regexp.Regexp
+}
+
+// RegisterRedactedFields registers JSON fields whose values are redacted by Redact, e.g. from logged bodies.
+// Resource packages register the fields holding personal data, e.g. printorder registers the recipient and delivery address.
+func RegisterRedactedFields(fields ...string) {
+	redactedFields.register(fields...)
+}
+
+// Registers fields.
+func (f *fieldRedactor) register(fields ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fields = append(f.fields, fields...)
+
+	quoted := make([]string, len(f.fields))
+	for k, v := range f.fields {
+		quoted[k] = regexp.QuoteMeta(v)
+	}
+
+	// Values cut off by truncation, missing the closing quote, are also matched.
+	f.re = regexp.MustCompile(`("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*(?:"|$)`)
+}
+
+// Redacts registered fields of s.
+func (f *fieldRedactor) redact(s string) string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.re == nil {
+		return s
+	}
+	return f.re.ReplaceAllString(s, `$1"`+REDACTED+`"`)
+}
+
+// Checks if field is registered.
+func (f *fieldRedactor) has(field string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, v := range f.fields {
+		if strings.EqualFold(v, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact redacts presigned URL signatures and fields registered with RegisterRedactedFields from s.
+func Redact(s string) string {
+	s = signatureParams.ReplaceAllString(s, "${1}"+REDACTED)
+	return redactedFields.redact(s)
+}
+
+// RedactQuery redacts the values of query params named after fields registered with RegisterRedactedFields, e.g.
+// "firstname=Jane", and presigned URL signatures from query. Params are sorted by name unless query is invalid.
+func RedactQuery(query string) string {
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return Redact(query)
+	}
+
+	for k, v := range q {
+		if redactedFields.has(k) {
+			for i := range v {
+				v[i] = REDACTED
+			}
+		}
+	}
+	return signatureParams.ReplaceAllString(q.Encode(), "${1}"+REDACTED)
+}
+
+// Returns a redacted body, truncated to at most limit bytes.
+func logBody(b []byte, limit int) string {
+	s := Redact(string(b))
+	if len(s) > limit {
+		// Back off to a rune boundary to keep the log valid UTF-8.
+		for limit > 0 && !utf8.RuneStart(s[limit]) {
+			limit--
+		}
+		return s[:limit] + "...(truncated)"
+	}
+	return s
+}
+
+// Logs call to endpoint.
+func (c APIClient) logCall(info *callInfo, req *http.Request, resp *http.Response, err error, latency time.Duration) {
+	l := c.Logging
+	if l == nil || l.Logger == nil {
+		return
+	}
+
+	level := l.Level
+	if err != nil || resp == nil || !successStatus(resp.StatusCode) {
+		level = l.ErrorLevel
+	}
+
+	ctx := req.Context()
+	if !l.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("endpoint", info.endpoint),
+	}
+
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", RedactQuery(req.URL.RawQuery)))
+	}
+
+	attrs = append(attrs,
+		slog.Int("attempts", info.attempts),
+		slog.Duration("latency", latency),
+	)
+
+	// The APICaller adds credentials to the request it sends, which is referenced by the response.
+	header := req.Header
+	if resp != nil && resp.Request != nil {
+		header = resp.Request.Header
+	}
+	if len(header) > 0 {
+		attrs = append(attrs, slog.Any("headers", RedactedHeader(header)))
+	}
+
+	if l.BodyLimit > 0 && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(io.LimitReader(body, LOG_BODY_READ_LIMIT))
+			body.Close()
+			attrs = append(attrs, slog.String("request_body", logBody(b, l.BodyLimit)))
+		}
+	}
+
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+
+		if l.BodyLimit > 0 && resp.Body != nil {
+			b, _ := io.ReadAll(io.LimitReader(resp.Body, LOG_BODY_READ_LIMIT))
+			// Put back what was read so that the body can still be decoded.
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+			attrs = append(attrs, slog.String("response_body", logBody(b, l.BodyLimit)))
+		}
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", Redact(err.Error())))
+	}
+
+	l.Logger.LogAttrs(ctx, level, LOG_MESSAGE, attrs...)
+}
@@ -0,0 +1,127 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCallsAreLogged(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusOK, `{"name":"test"}`),
+		},
+		Errors: []error{nil, nil},
+	}
+	c := &APIClient{
+		Client:  caller,
+		BaseUrl: "somebaseurl",
+		Retry:   &RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}},
+		Logging: DefaultLogging(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	}
+
+	i := &struct {
+		Name string `json:"name"`
+	}{}
+	if err := c.Get(NewEndpoint(), i, func(q url.Values) { q.Set("limit", "1") }); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if i.Name != "test" {
+		t.Errorf(`Expected response body to be decoded after logging, got name: "%s"`, i.Name)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal("Could not parse log record.", err)
+	}
+
+	expected := map[string]interface{}{
+		"level":         "DEBUG",
+		"msg":           LOG_MESSAGE,
+		"method":        "GET",
+		"endpoint":      "someendpoint",
+		"query":         "limit=1",
+		"attempts":      float64(2),
+		"status":        float64(200),
+		"response_body": `{"name":"test"}`,
+	}
+	for k, v := range expected {
+		if record[k] != v {
+			t.Errorf(`Log attribute "%s" did not match expected. Got: "%v", expected: "%v"`, k, record[k], v)
+		}
+	}
+}
+
+func TestLoggedBodiesAreTruncatedOnRuneBoundary(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	caller := &SequenceAPICaller{Responses: []*http.Response{createCallerResponse(http.StatusOK, `{"name":"åäö"}`)}, Errors: []error{nil}}
+	l := DefaultLogging(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	// Cuts in the middle of "å".
+	l.BodyLimit = 10
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Logging: l}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal("Could not parse log record.", err)
+	}
+
+	if record["response_body"] != `{"name":"...(truncated)` {
+		t.Errorf(`Expected body to be truncated before "å" but got: "%v"`, record["response_body"])
+	}
+}
+
+func TestFailedCallsAreLoggedAtErrorLevel(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	caller := &SequenceAPICaller{Errors: []error{errors.New("Some error")}, Responses: []*http.Response{nil}}
+	c := &APIClient{
+		Client:  caller,
+		BaseUrl: "somebaseurl",
+		Logging: DefaultLogging(slog.New(slog.NewTextHandler(&buf, nil))),
+	}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	if !strings.Contains(buf.String(), "level=WARN") || !strings.Contains(buf.String(), "Some error") {
+		t.Errorf(`Expected failed call to be logged with error at warning level, got: "%s"`, buf.String())
+	}
+}
+
+func TestLoggedCallsAreRedacted(t *testing.T) {
+	t.Parallel()
+	RegisterRedactedFields("secret_name")
+
+	var buf bytes.Buffer
+	resp := createCallerResponse(http.StatusOK, `{"secret_name":"John \"Doe\"","presigned_url":"https://s3/file?X-Amz-Credential=key&X-Amz-Signature=abc123"}`)
+	c := &APIClient{
+		Client:  &AuthorizingAPICaller{Response: resp},
+		BaseUrl: "somebaseurl",
+		Logging: DefaultLogging(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	}
+
+	c.Post(NewEndpoint(), &struct{}{}, &struct{}{})
+	c.Get(NewEndpoint(), &struct{}{}, func(q url.Values) { q.Set("secret_name", "Johnny") })
+
+	for _, v := range []string{"Bearer secret", "John", "Doe", "abc123", "key"} {
+		if strings.Contains(buf.String(), v) {
+			t.Errorf(`Expected "%s" to be redacted from log, got: "%s"`, v, buf.String())
+		}
+	}
+
+	if !strings.Contains(buf.String(), "https://s3/file") {
+		t.Errorf(`Expected presigned URL to be logged without signature, got: "%s"`, buf.String())
+	}
+}
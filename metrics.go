@@ -0,0 +1,60 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"time"
+)
+
+// Metrics receives measurements of SDK activity.
+// The prommetrics package provides a Prometheus implementation, which keeps this package free of dependencies.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Observes a completed call to an endpoint template, e.g. "print_orders/{id}".
+	// Status is the response status code, or 0 if no response was received.
+	ObserveRequest(method string, endpoint string, status int, latency time.Duration)
+	// Counts a retried attempt of a call.
+	IncRetry(method string, endpoint string)
+	// Counts a token refresh. Err is the error of the refresh, if any.
+	IncTokenRefresh(err error)
+	// Counts a presigned URL fetch. Err is the error of the fetch, if any.
+	IncPresignedFetch(err error)
+	// Adds n downloaded bytes.
+	AddDownloadBytes(n int64)
+	// Counts a failed file download.
+	IncDownloadFailure()
+}
+
+// NopMetrics is a Metrics discarding all measurements.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveRequest(method string, endpoint string, status int, latency time.Duration) {}
+func (NopMetrics) IncRetry(method string, endpoint string)                                          {}
+func (NopMetrics) IncTokenRefresh(err error)                                                        {}
+func (NopMetrics) IncPresignedFetch(err error)                                                      {}
+func (NopMetrics) AddDownloadBytes(n int64)                                                         {}
+func (NopMetrics) IncDownloadFailure()                                                              {}
+
+// Measuring is implemented by clients that can provide Metrics, such as APIClient.
+// Resource packages use it to measure work done outside of API calls, e.g. file downloads.
+type Measuring interface {
+	CallMetrics() Metrics
+}
+
+// Returns the Metrics of the client. NopMetrics if none is set.
+func (c APIClient) CallMetrics() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics{}
+	}
+	return c.Metrics
+}
+
+// Returns the Metrics of c if it implements Measuring, otherwise NopMetrics.
+func MetricsOf(c interface{}) Metrics {
+	if m, ok := c.(Measuring); ok {
+		if metrics := m.CallMetrics(); metrics != nil {
+			return metrics
+		}
+	}
+	return NopMetrics{}
+}
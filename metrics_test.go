@@ -0,0 +1,89 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallsAreMeasured(t *testing.T) {
+	t.Parallel()
+	m := &RecordingMetrics{}
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusServiceUnavailable, `{}`),
+			createCallerResponse(http.StatusOK, `{}`),
+		},
+		Errors: []error{nil, nil},
+	}
+	c := &APIClient{
+		Client:  caller,
+		BaseUrl: "somebaseurl",
+		Retry:   &RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusServiceUnavailable}},
+		Metrics: m,
+	}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	if len(m.Requests) != 1 || m.Requests[0] != "GET someendpoint 200" {
+		t.Errorf(`Expected one observed request but got: "%v"`, m.Requests)
+	}
+
+	if m.Retries != 1 {
+		t.Errorf("Expected 1 retry but got %d.", m.Retries)
+	}
+}
+
+func TestTokenRefreshesAreMeasured(t *testing.T) {
+	t.Parallel()
+	m := &RecordingMetrics{}
+	c := &APIClient{Client: &TokenAPICaller{}, BaseUrl: "somebaseurl", Metrics: m}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	if m.TokenRefreshes != 1 {
+		t.Errorf("Expected 1 token refresh but got %d.", m.TokenRefreshes)
+	}
+}
+
+func TestMetricsOfClientWithoutMetricsIsNop(t *testing.T) {
+	t.Parallel()
+	if _, ok := MetricsOf(APIClient{}).(NopMetrics); !ok {
+		t.Error("Expected NopMetrics for client without metrics.")
+	}
+
+	if _, ok := MetricsOf(struct{}{}).(NopMetrics); !ok {
+		t.Error("Expected NopMetrics for value not implementing Measuring.")
+	}
+}
+
+// Metrics recording measurements.
+type RecordingMetrics struct {
+	NopMetrics
+
+	mu             sync.Mutex
+	Requests       []string
+	Retries        int
+	TokenRefreshes int
+}
+
+func (m *RecordingMetrics) ObserveRequest(method string, endpoint string, status int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Requests = append(m.Requests, fmt.Sprintf("%s %s %d", method, endpoint, status))
+}
+
+func (m *RecordingMetrics) IncRetry(method string, endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Retries++
+}
+
+func (m *RecordingMetrics) IncTokenRefresh(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.TokenRefreshes++
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Version of the SDK, sent in the User-Agent header by the UserAgent middleware.
+const SDK_VERSION = "1.0.0"
+
+// Header constants used by the built-in middleware.
+const (
+	HEADER_REQUEST_ID = "X-Request-Id"
+	HEADER_USER_AGENT = "User-Agent"
+)
+
+// Value replacing redacted header values.
+const REDACTED = "[REDACTED]"
+
+// RoundTripFunc performs a single HTTP call.
+type RoundTripFunc func(r *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to inspect or modify requests and responses.
+//
+// Middleware registered on an APIClient are applied to every call made by Get, Post, Put, Delete and StatusCheck,
+// including retries and replays after token refresh. The first middleware sees the request first and the response last.
+// Middleware must not modify the request passed to them, but may pass a clone to next.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Chains middleware around rt. The first middleware is the outermost.
+func chain(rt RoundTripFunc, middleware []Middleware) RoundTripFunc {
+	for k := len(middleware) - 1; k >= 0; k-- {
+		rt = middleware[k](rt)
+	}
+	return rt
+}
+
+// Context key for request IDs.
+type requestIDKey struct{}
+
+// Returns a copy of ctx with request ID id. The RequestID middleware sends it instead of generating a new one,
+// which lets the ID of a call be known beforehand or shared between several calls.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// Returns the request ID of ctx, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID returns a Middleware setting the X-Request-Id header of requests that do not already have one.
+// The ID is taken from the request context if set with WithRequestID. Otherwise the APIClient generates a random ID
+// per call, shared by its retries and replays after token refresh.
+func RequestID() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if r.Header.Get(HEADER_REQUEST_ID) != "" {
+				return next(r)
+			}
+
+			id, ok := RequestIDFromContext(r.Context())
+			if !ok {
+				id = newRequestID()
+			}
+
+			r = r.Clone(r.Context())
+			r.Header.Set(HEADER_REQUEST_ID, id)
+			return next(r)
+		}
+	}
+}
+
+// Generates random request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// UserAgent returns a Middleware setting the User-Agent header to "ProductionAPIGoSDK/<SDK_VERSION>".
+// Product, e.g. "myapp/1.2", is prepended if given.
+func UserAgent(product string) Middleware {
+	ua := "ProductionAPIGoSDK/" + SDK_VERSION
+	if product != "" {
+		ua = product + " " + ua
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			r = r.Clone(r.Context())
+			r.Header.Set(HEADER_USER_AGENT, ua)
+			return next(r)
+		}
+	}
+}
+
+// Headers redacted by RedactHeaders if none are given.
+var DefaultRedactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// RedactHeaders returns a Middleware redacting the given headers, or DefaultRedactedHeaders if none are given,
+// from the response and from the request it references. This keeps credentials added by the APICaller
+// out of errors and anything inspecting the response further up the chain.
+func RedactHeaders(names ...string) Middleware {
+	if len(names) == 0 {
+		names = DefaultRedactedHeaders
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			resp, err := next(r)
+			if resp == nil {
+				return resp, err
+			}
+
+			req := resp.Request
+			if req == nil {
+				req = r
+			}
+			req = req.Clone(req.Context())
+			resp.Request = req
+
+			redactHeader(req.Header, names)
+			redactHeader(resp.Header, names)
+			return resp, err
+		}
+	}
+}
+
+// Returns a copy of h with the given headers redacted.
+func RedactedHeader(h http.Header, names ...string) http.Header {
+	if len(names) == 0 {
+		names = DefaultRedactedHeaders
+	}
+
+	h = h.Clone()
+	redactHeader(h, names)
+	return h
+}
+
+// Redacts the given headers of h in place.
+func redactHeader(h http.Header, names []string) {
+	for k, v := range h {
+		for _, n := range names {
+			if strings.EqualFold(k, n) {
+				for i := range v {
+					v[i] = REDACTED
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareIsAppliedInOrder(t *testing.T) {
+	t.Parallel()
+	var order []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(r *http.Request) (*http.Response, error) {
+				order = append(order, name+" request")
+				resp, err := next(r)
+				order = append(order, name+" response")
+				return resp, err
+			}
+		}
+	}
+
+	caller := &SequenceAPICaller{Responses: []*http.Response{createCallerResponse(http.StatusOK, `{}`)}}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Middleware: []Middleware{record("first"), record("second")}}
+
+	if err := c.Get(NewEndpoint(), &struct{}{}); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	expected := []string{"first request", "second request", "second response", "first response"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf(`Middleware order did not match expected. Got: "%v", expected: "%v"`, order, expected)
+	}
+}
+
+func TestMiddlewareIsAppliedToAllMethods(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	count := func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			calls++
+			return next(r)
+		}
+	}
+
+	responses := make([]*http.Response, 5)
+	for k := range responses {
+		responses[k] = createCallerResponse(http.StatusOK, `{}`)
+	}
+	caller := &SequenceAPICaller{Responses: responses}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Middleware: []Middleware{count}}
+
+	i := &struct{}{}
+	c.Get(NewEndpoint(), i)
+	c.Post(NewEndpoint(), i, i)
+	c.Put(NewEndpoint(), i, i)
+	c.Delete(NewEndpoint(), i)
+	c.StatusCheck()
+
+	if calls != 5 {
+		t.Errorf("Expected middleware to be called 5 times but got %d.", calls)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{Responses: []*http.Response{
+		createCallerResponse(http.StatusOK, `{}`),
+		createCallerResponse(http.StatusOK, `{}`),
+	}}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Middleware: []Middleware{RequestID()}}
+
+	c.Get(NewEndpoint(), &struct{}{})
+	if id := caller.Requests[0].Header.Get(HEADER_REQUEST_ID); len(id) != 32 {
+		t.Errorf(`Expected generated request ID but got: "%s"`, id)
+	}
+
+	c.GetContext(WithRequestID(context.Background(), "someid"), NewEndpoint(), &struct{}{})
+	if id := caller.Requests[1].Header.Get(HEADER_REQUEST_ID); id != "someid" {
+		t.Errorf(`Expected request ID from context but got: "%s"`, id)
+	}
+}
+
+func TestRequestIDIsSharedByAllAttemptsOfACall(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{Responses: []*http.Response{
+		createCallerResponse(http.StatusBadGateway, `{}`),
+		createCallerResponse(http.StatusUnauthorized, `{}`),
+		createCallerResponse(http.StatusOK, `{}`),
+		createCallerResponse(http.StatusOK, `{}`),
+	}}
+	c := &APIClient{
+		Client:     caller,
+		BaseUrl:    "somebaseurl",
+		Retry:      &RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}},
+		Middleware: []Middleware{RequestID()},
+	}
+
+	if err := c.Get(NewEndpoint(), &struct{}{}); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	if len(caller.Requests) != 3 {
+		t.Fatalf("Expected a retry and a replay after token refresh but got %d attempts.", len(caller.Requests))
+	}
+
+	id := caller.Requests[0].Header.Get(HEADER_REQUEST_ID)
+	for k, r := range caller.Requests {
+		if got := r.Header.Get(HEADER_REQUEST_ID); got == "" || got != id {
+			t.Errorf(`Expected attempt %d to have request ID "%s" but got: "%s"`, k+1, id, got)
+		}
+	}
+
+	c.Get(NewEndpoint(), &struct{}{})
+	if caller.Requests[3].Header.Get(HEADER_REQUEST_ID) == id {
+		t.Error("Expected a new request ID for a new call.")
+	}
+}
+
+func TestUserAgentMiddleware(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{Responses: []*http.Response{createCallerResponse(http.StatusOK, `{}`)}}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Middleware: []Middleware{UserAgent("myapp/1.2")}}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	expected := "myapp/1.2 ProductionAPIGoSDK/" + SDK_VERSION
+	if ua := caller.Requests[0].Header.Get(HEADER_USER_AGENT); ua != expected {
+		t.Errorf(`User-Agent did not match expected. Got: "%s", expected: "%s"`, ua, expected)
+	}
+}
+
+func TestRedactHeadersMiddleware(t *testing.T) {
+	t.Parallel()
+	resp := createCallerResponse(http.StatusNotFound, `{}`)
+	resp.Header = http.Header{"Set-Cookie": []string{"session=secret"}}
+	caller := &AuthorizingAPICaller{Response: resp}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Middleware: []Middleware{RedactHeaders()}}
+
+	err := c.Get(NewEndpoint(), &struct{}{})
+
+	re, ok := err.(*ResponseError)
+	if !ok {
+		t.Fatalf(`Expected ResponseError but got: "%v"`, err)
+	}
+
+	if v := re.Header.Get("Set-Cookie"); v != REDACTED {
+		t.Errorf(`Expected response header to be redacted but got: "%s"`, v)
+	}
+
+	if v := resp.Request.Header.Get("Authorization"); v != REDACTED {
+		t.Errorf(`Expected request header to be redacted but got: "%s"`, v)
+	}
+
+	if strings.Contains(fmt.Sprint(re), "secret") {
+		t.Error("Expected error not to contain secrets.")
+	}
+}
+
+// APICaller mock adding an Authorization header to requests, like the APIUtilityGoSDK client.
+type AuthorizingAPICaller struct {
+	Response *http.Response
+}
+
+func (c *AuthorizingAPICaller) Call(r *http.Request) (*http.Response, error) {
+	r.Header.Set("Authorization", "Bearer secret")
+	c.Response.Request = r
+	return c.Response, nil
+}
+
+func (c *AuthorizingAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *AuthorizingAPICaller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
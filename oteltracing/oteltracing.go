@@ -0,0 +1,99 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+// Traces Publit production API calls with OpenTelemetry.
+//
+// Set a Tracer on the production.APIClient to get a span per call, named after the endpoint template,
+// with the W3C trace context propagated to the API:
+//
+//	c := production.APIClient{
+//		Client:  client.New(...),
+//		BaseUrl: "https://url.to.publit",
+//		Tracer:  oteltracing.New(nil),
+//	}
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// Name of the instrumentation scope.
+const INSTRUMENTATION_NAME = "github.com/publitsweden/ProductionAPIGoSDK"
+
+// Tracer implements production.Tracer using OpenTelemetry.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// Creates new Tracer using tp, or the global TracerProvider if nil.
+// Trace context is propagated using W3C trace context headers.
+func New(tp trace.TracerProvider) *Tracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return &Tracer{
+		tracer:     tp.Tracer(INSTRUMENTATION_NAME),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// Start method to fulfil the production.Tracer interface.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, production.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+	return ctx, span{s}
+}
+
+// Inject method to fulfil the production.Tracer interface.
+func (t *Tracer) Inject(ctx context.Context, h http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// Span wrapping an OpenTelemetry span.
+type span struct {
+	s trace.Span
+}
+
+// SetAttribute method to fulfil the production.Span interface.
+func (s span) SetAttribute(key string, value interface{}) {
+	var kv attribute.KeyValue
+	switch v := value.(type) {
+	case string:
+		kv = attribute.String(key, v)
+	case int:
+		kv = attribute.Int(key, v)
+	case int64:
+		kv = attribute.Int64(key, v)
+	case float64:
+		kv = attribute.Float64(key, v)
+	case bool:
+		kv = attribute.Bool(key, v)
+	default:
+		kv = attribute.String(key, fmt.Sprint(v))
+	}
+	s.s.SetAttributes(kv)
+}
+
+// RecordError method to fulfil the production.Span interface.
+func (s span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+// SetErrorStatus method to fulfil the production.Span interface.
+func (s span) SetErrorStatus(description string) {
+	s.s.SetStatus(codes.Error, description)
+}
+
+// End method to fulfil the production.Span interface.
+func (s span) End() {
+	s.s.End()
+}
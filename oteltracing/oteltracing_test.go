@@ -0,0 +1,64 @@
+package oteltracing
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"testing"
+)
+
+func TestSpansAreRecorded(t *testing.T) {
+	t.Parallel()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := New(tp)
+
+	_, s := tracer.Start(context.Background(), "print_orders/{id}")
+	s.SetAttribute("http.response.status_code", 404)
+	s.SetAttribute("publit.endpoint", "print_orders/4")
+	s.RecordError(errors.New("Some error"))
+	s.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span but got %d.", len(spans))
+	}
+
+	got := spans[0]
+	if got.Name != "print_orders/{id}" {
+		t.Errorf(`Span name did not match expected. Got: "%s"`, got.Name)
+	}
+
+	if got.Status.Code != codes.Error {
+		t.Error("Expected span status to be error.")
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		"http.response.status_code": attribute.IntValue(404),
+		"publit.endpoint":           attribute.StringValue("print_orders/4"),
+	}
+	for _, v := range got.Attributes {
+		if e, ok := expected[v.Key]; ok && e != v.Value {
+			t.Errorf(`Attribute "%s" did not match expected. Got: "%v", expected: "%v"`, v.Key, v.Value.Emit(), e.Emit())
+		}
+	}
+}
+
+func TestTraceContextIsInjected(t *testing.T) {
+	t.Parallel()
+	tracer := New(sdktrace.NewTracerProvider())
+
+	ctx, s := tracer.Start(context.Background(), "print_orders")
+	defer s.End()
+
+	h := http.Header{}
+	tracer.Inject(ctx, h)
+
+	if h.Get("Traceparent") == "" {
+		t.Error("Expected W3C traceparent header to be injected.")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+)
+
+// Default maximum number of items collected by IndexAll functions.
+const DEFAULT_INDEX_ALL_LIMIT = 10000
+
+// Returned by Collect when there are more items than the given limit.
+var ErrTooManyItems = errors.New("Index contains more items than the given limit.")
+
+// PageFetcher fetches a single page of an index.
+// Returns the items of the page and the link to the next page, which is empty on the last page.
+type PageFetcher[T any] func(ctx context.Context, queryParams ...func(q url.Values)) ([]T, string, error)
+
+// Returns an iterator over all items of an index, following the Next link of each page.
+// The query parameters of the Next link are applied on top of queryParams, so filters not included in the link are kept.
+// Iteration stops after the first error, which is yielded together with the zero value of T.
+func Paginate[T any](ctx context.Context, fetch PageFetcher[T], queryParams ...func(q url.Values)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		params := queryParams
+		seen := map[string]bool{}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			items, next, err := fetch(ctx, params...)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, v := range items {
+				if !yield(v, nil) {
+					return
+				}
+			}
+
+			// Stop on last page, empty page or if the API links back to an already visited page.
+			if next == "" || len(items) == 0 || seen[next] {
+				return
+			}
+			seen[next] = true
+
+			nextParams, err := queryParamsFromLink(next)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			params = append(append([]func(q url.Values){}, queryParams...), nextParams)
+		}
+	}
+}
+
+// Collects the items of seq into a slice.
+// At most max items are collected, if there are more ErrTooManyItems is returned together with the collected items.
+// DEFAULT_INDEX_ALL_LIMIT is used if max is zero or less.
+func Collect[T any](seq iter.Seq2[T, error], max int) ([]T, error) {
+	if max <= 0 {
+		max = DEFAULT_INDEX_ALL_LIMIT
+	}
+
+	var l []T
+	for v, err := range seq {
+		if err != nil {
+			return l, err
+		}
+
+		if len(l) == max {
+			return l, ErrTooManyItems
+		}
+
+		l = append(l, v)
+	}
+
+	return l, nil
+}
+
+// Creates a query param function setting the query of a Next or Prev link.
+func queryParamsFromLink(link string) (func(q url.Values), error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+
+	lq := u.Query()
+	return func(q url.Values) {
+		for k, v := range lq {
+			q[k] = v
+		}
+	}, nil
+}
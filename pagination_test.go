@@ -0,0 +1,125 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestPaginateFollowsNextLinks(t *testing.T) {
+	t.Parallel()
+	pages := map[string]struct {
+		Items []int
+		Next  string
+	}{
+		"":  {[]int{1, 2}, "https://url.to.publit/production/v2.0/someendpoint?offset=2"},
+		"2": {[]int{3, 4}, "https://url.to.publit/production/v2.0/someendpoint?offset=4"},
+		"4": {[]int{5}, ""},
+	}
+
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]int, string, error) {
+		q := url.Values{}
+		for _, v := range queryParams {
+			v(q)
+		}
+
+		if q.Get("filter") != "kept" {
+			t.Error("Caller filter was not kept when following next link.")
+		}
+
+		p := pages[q.Get("offset")]
+		return p.Items, p.Next, nil
+	}
+
+	filter := func(q url.Values) { q.Set("filter", "kept") }
+
+	l, err := Collect(Paginate(context.Background(), fetch, filter), 0)
+
+	if err != nil {
+		t.Error("Got error but was not expecting one.", err)
+	}
+
+	if len(l) != 5 {
+		t.Errorf("Expected 5 items but got %d.", len(l))
+	}
+
+	for k, v := range l {
+		if v != k+1 {
+			t.Errorf(`Items out of order. Got "%d" at index "%d".`, v, k)
+		}
+	}
+}
+
+func TestPaginateStopsOnError(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]int, string, error) {
+		calls++
+		if calls > 1 {
+			return nil, "", errors.New("Some error")
+		}
+		return []int{1}, "?offset=1", nil
+	}
+
+	l, err := Collect(Paginate(context.Background(), fetch), 0)
+
+	if err == nil {
+		t.Error("Did not receive an error but was expecting one.")
+	}
+
+	if len(l) != 1 {
+		t.Errorf("Expected items before error to be kept. Got %d items.", len(l))
+	}
+}
+
+func TestPaginateStopsOnLoopingLinks(t *testing.T) {
+	t.Parallel()
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]int, string, error) {
+		return []int{1}, "?offset=1", nil
+	}
+
+	l, err := Collect(Paginate(context.Background(), fetch), 0)
+
+	if err != nil {
+		t.Error("Got error but was not expecting one.", err)
+	}
+
+	if len(l) != 2 {
+		t.Errorf("Expected iteration to stop when revisiting a page. Got %d items.", len(l))
+	}
+}
+
+func TestPaginateStopsOnCancelledContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]int, string, error) {
+		cancel()
+		return []int{1}, "?offset=1", nil
+	}
+
+	_, err := Collect(Paginate(ctx, fetch), 0)
+
+	if err != context.Canceled {
+		t.Errorf(`Expected context.Canceled but got: "%v"`, err)
+	}
+}
+
+func TestCollectHonorsLimit(t *testing.T) {
+	t.Parallel()
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]int, string, error) {
+		return []int{1, 2, 3}, "", nil
+	}
+
+	l, err := Collect(Paginate(context.Background(), fetch), 2)
+
+	if err != ErrTooManyItems {
+		t.Errorf(`Expected ErrTooManyItems but got: "%v"`, err)
+	}
+
+	if len(l) != 2 {
+		t.Errorf("Expected 2 items but got %d.", len(l))
+	}
+}
@@ -8,15 +8,15 @@
 package printdata
 
 import (
-	"fmt"
+	"context"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
 	"github.com/publitsweden/ProductionAPIGoSDK/file"
 	"github.com/publitsweden/ProductionAPIGoSDK/printdata/bookbinding"
 	"github.com/publitsweden/ProductionAPIGoSDK/printdata/manifestation"
 	"github.com/publitsweden/ProductionAPIGoSDK/printdata/printitempaper"
+	"iter"
 	"net/url"
-	"strings"
 )
 
 // With constants. Use for loading relations from PrintData.
@@ -44,11 +44,8 @@ const (
 	SHOW
 )
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX: "print_order_print_data",
-	SHOW:  "print_order_print_data/%v",
-}
+// Generic resource backing the package functions.
+var resource = production.NewResource[PrintData, IndexResponse]("print_order_print_data")
 
 // PrintDataList type.
 type PrintDataList []*PrintData
@@ -110,32 +107,62 @@ type PrintData struct {
 }
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
 
 // Returns PrintDAta from Publit API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*PrintData, error) {
-	pd := &PrintData{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, pd, queryParams...)
-	return pd, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
 }
 
-// Index response object.
-type IndexResponse struct {
-	Count int           `json:"count"`
-	Next  string        `json:"next"`
-	Prev  string        `json:"prev"`
-	Data  PrintDataList `json:"data"`
+// Returns PrintData from Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*PrintData, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
+// Index response object.
+type IndexResponse production.IndexResponse[PrintDataList]
+
 // Indexes PrintData from the Publit API.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Indexes PrintData from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
+
+// Iterates all PrintData matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[*PrintData, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all PrintData matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[*PrintData, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]*PrintData, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
+	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all PrintData matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) (PrintDataList, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all PrintData matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) (PrintDataList, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return PrintDataList(l), err
 }
 
 // Compiles PrintDataList to amp indexed on manifestation id.
@@ -153,12 +180,8 @@ func (data PrintDataList) GetPrintDataPerManifestation() map[int][]*PrintData {
 
 // Method to Resource that fullfils the Enpointer interface as stated in production.
 func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
-
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+	if r.Endpoint == SHOW {
+		return resource.Endpoint(r.Id).GetEndpoint()
 	}
-
-	return end
+	return resource.Endpoint(0).GetEndpoint()
 }
@@ -0,0 +1,43 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printdata
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"strconv"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, PRINT_ORDER_ID, MANIFESTATION_ID, FILE_ID, PRINT_ITEM_PAPER_ID, BOOK_BINDING_ID,
+	AMOUNT, PAGES, WIDTH, HEIGHT, COLOR_PAGES_AMOUNT, COLOR_PAGES, REFERENCE_NUMBER, COLOR_PRINT,
+	LENGTH_UNIT, FORMAT, PUBLISHER, TITLE, SUBTITLE, EDGE_WIDTH, CREATED_AT, UPDATED_AT,
+}
+
+// Relations that can be loaded with the response.
+var relations []string = []string{
+	WITH_MANIFESTATION, WITH_MANIFESTATION_ISBN, WITH_FILE, WITH_PRINT_ITEM_PAPER, WITH_PRINT_ITEM, WITH_BOOK_BINDING,
+}
+
+// QueryBuilder builds query parameters for indexing PrintData.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for PrintData.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, relations)
+	return q
+}
+
+// Filters on PrintData for print order.
+func (q *QueryBuilder) PrintOrderID(id int) *QueryBuilder {
+	return q.Where(PRINT_ORDER_ID, production.OPERATOR_EQUAL, strconv.Itoa(id))
+}
+
+// Filters on PrintData for manifestation.
+func (q *QueryBuilder) ManifestationID(id int) *QueryBuilder {
+	return q.Where(MANIFESTATION_ID, production.OPERATOR_EQUAL, strconv.Itoa(id))
+}
@@ -0,0 +1,359 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorder
+
+import (
+	"errors"
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Address problem codes.
+const (
+	// A required field is empty.
+	ADDRESS_MISSING = "missing"
+	// A field does not match the format of the delivery country.
+	ADDRESS_INVALID = "invalid"
+	// The delivery country could not be resolved.
+	ADDRESS_UNKNOWN_COUNTRY = "unknown_country"
+)
+
+// AddressProblem is a problem with a field of the delivery address of a PrintOrder.
+// Messages never contain the field values, as they are personal data.
+type AddressProblem struct {
+	// Attribute of the field, e.g. DELIVERY_ZIP.
+	Field   string
+	Code    string
+	Message string
+}
+
+// Error method to fulfil the error interface.
+func (p AddressProblem) Error() string {
+	return p.Field + ": " + p.Message
+}
+
+// AddressValidation is the outcome of validating the delivery address of a PrintOrder.
+type AddressValidation struct {
+	PrintOrderID int
+	// Resolved delivery country, nil if it could not be resolved.
+	Country *country.Country
+	// Postal code in the format of the delivery country, e.g. "111 22" for "11122" in Sweden.
+	// Empty if the postal code is invalid or the country has no known format.
+	PostalCode string
+	// Phone number in E.164 format, e.g. "+46812345678". Empty if the phone number is missing or invalid.
+	Phone    string
+	Problems []AddressProblem
+}
+
+// Checks if the address has no problems.
+func (v *AddressValidation) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+// Returns the problems joined into one error, or nil if there are none.
+func (v *AddressValidation) Err() error {
+	l := make([]error, len(v.Problems))
+	for k, p := range v.Problems {
+		l[k] = p
+	}
+	return errors.Join(l...)
+}
+
+// Adds problem with field.
+func (v *AddressValidation) add(field, code, format string, a ...interface{}) {
+	v.Problems = append(v.Problems, AddressProblem{Field: field, Code: code, Message: fmt.Sprintf(format, a...)})
+}
+
+// Validates the delivery address of the PrintOrder before it is handed to a carrier.
+//
+// The delivery country is taken from DeliveryCountry if it is loaded, see WITH_DELIVERY_COUNTRY, and otherwise
+// looked up by DeliveryCountryId in the embedded dataset, which requires country.Sync to have been run.
+// The postal code is checked against the format of the country and the phone number, which is optional,
+// is normalized to E.164 using the calling code of the country.
+func (p *PrintOrder) ValidateAddress() *AddressValidation {
+	v := &AddressValidation{PrintOrderID: p.ID}
+
+	if strings.TrimSpace(p.DeliveryStreet) == "" {
+		v.add(DELIVERY_STREET, ADDRESS_MISSING, "Street is missing.")
+	}
+	if strings.TrimSpace(p.DeliveryCity) == "" {
+		v.add(DELIVERY_CITY, ADDRESS_MISSING, "City is missing.")
+	}
+
+	v.Country = p.deliveryCountry()
+	if v.Country == nil {
+		if p.DeliveryCountryId == "" && p.DeliveryCountry == nil {
+			v.add(DELIVERY_COUNTRY_ID, ADDRESS_MISSING, "Delivery country is missing.")
+		} else {
+			v.add(DELIVERY_COUNTRY_ID, ADDRESS_UNKNOWN_COUNTRY, `Delivery country "%s" is unknown. Load it with WITH_DELIVERY_COUNTRY or run country.Sync.`, p.DeliveryCountryId)
+		}
+	}
+	m := v.Country.Metadata()
+
+	zip := normalizePostalCode(p.DeliveryZip)
+	switch {
+	case zip == "" && m != nil && m.PostalCodes:
+		v.add(DELIVERY_ZIP, ADDRESS_MISSING, "Postal code is missing.")
+	case zip != "" && v.Country != nil:
+		code, ok := PostalCode(zip, v.Country)
+		if !ok {
+			v.add(DELIVERY_ZIP, ADDRESS_INVALID, `Postal code does not match the format of %s, e.g. "%s".`, v.Country.Name, postalFormats[strings.ToUpper(v.Country.ISO2)].example)
+		}
+		v.PostalCode = code
+	}
+
+	if strings.TrimSpace(p.DeliveryPhone) != "" {
+		phone, err := E164(p.DeliveryPhone, v.Country)
+		if err != nil {
+			v.add(DELIVERY_PHONE, ADDRESS_INVALID, "%s", err)
+		}
+		v.Phone = phone
+	}
+
+	return v
+}
+
+// Returns the delivery country of the PrintOrder, or nil if it cannot be resolved.
+func (p *PrintOrder) deliveryCountry() *country.Country {
+	if p.DeliveryCountry != nil && p.DeliveryCountry.ISO2 != "" {
+		if c := country.ByISO2(p.DeliveryCountry.ISO2); c != nil {
+			return c
+		}
+		c := *p.DeliveryCountry
+		return &c
+	}
+
+	id, err := strconv.Atoi(p.DeliveryCountryId)
+	if err != nil {
+		return nil
+	}
+	return country.ByID(id)
+}
+
+// AddressReport is the outcome of validating the delivery addresses of several PrintOrders.
+type AddressReport struct {
+	// Number of validated PrintOrders.
+	Checked int
+	// Validations of the PrintOrders with problems, in the order they were validated.
+	Invalid []*AddressValidation
+}
+
+// Returns the number of problems per problem code, e.g. ADDRESS_INVALID.
+func (r *AddressReport) Counts() map[string]int {
+	m := map[string]int{}
+	for _, v := range r.Invalid {
+		for _, p := range v.Problems {
+			m[p.Code]++
+		}
+	}
+	return m
+}
+
+// Validates the delivery addresses of the PrintOrders, see ValidateAddress.
+func ValidateAddresses(l []PrintOrder) *AddressReport {
+	r := &AddressReport{Checked: len(l)}
+	for k := range l {
+		if v := l[k].ValidateAddress(); !v.Valid() {
+			r.Invalid = append(r.Invalid, v)
+		}
+	}
+	return r
+}
+
+// Validates the delivery addresses of the PrintOrders of the index page, see ValidateAddress.
+func (r *IndexResponse) ValidateAddresses() *AddressReport {
+	return ValidateAddresses(r.Data)
+}
+
+// Postal code format of a country.
+type postalFormat struct {
+	pattern *regexp.Regexp
+	// Replacement template of pattern giving the canonical format.
+	template string
+	example  string
+}
+
+// Creates postal code format. Patterns match postal codes that are uppercased and have single spaces.
+func postal(pattern, template, example string) postalFormat {
+	return postalFormat{pattern: regexp.MustCompile(pattern), template: template, example: example}
+}
+
+// Formats of British and US postal codes, which are also used by dependencies.
+var (
+	postalGB = postal(`^([A-Z]{1,2}\d[A-Z\d]?) ?(\d[A-Z]{2})$`, "${1} ${2}", "SW1A 1AA")
+	postalUS = postal(`^(\d{5})(?:-?(\d{4}))?$`, "${1}-${2}", "12345-6789")
+)
+
+// Postal code formats by alpha-2 code. Countries that use postal codes but are not listed only need one to be present.
+var postalFormats = map[string]postalFormat{
+	"AD": postal(`^AD ?(\d{3})$`, "AD${1}", "AD500"),
+	"AR": postal(`^([A-Z]\d{4}[A-Z]{3}|\d{4})$`, "${1}", "C1425DKD"),
+	"AS": postalUS,
+	"AT": postal(`^(\d{4})$`, "${1}", "1010"),
+	"AU": postal(`^(\d{4})$`, "${1}", "2000"),
+	"AX": postal(`^(22\d{3})$`, "AX-${1}", "AX-22100"),
+	"BE": postal(`^(\d{4})$`, "${1}", "1000"),
+	"BG": postal(`^(\d{4})$`, "${1}", "1000"),
+	"BR": postal(`^(\d{5})-?(\d{3})$`, "${1}-${2}", "01310-100"),
+	"BY": postal(`^(\d{6})$`, "${1}", "220030"),
+	"CA": postal(`^([ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z]) ?(\d[ABCEGHJ-NPRSTV-Z]\d)$`, "${1} ${2}", "K1A 0B1"),
+	"CH": postal(`^(\d{4})$`, "${1}", "8001"),
+	"CN": postal(`^(\d{6})$`, "${1}", "100000"),
+	"CY": postal(`^(\d{4})$`, "${1}", "1010"),
+	"CZ": postal(`^(\d{3}) ?(\d{2})$`, "${1} ${2}", "110 00"),
+	"DE": postal(`^(\d{5})$`, "${1}", "10115"),
+	"DK": postal(`^(\d{4})$`, "${1}", "1050"),
+	"EE": postal(`^(\d{5})$`, "${1}", "10111"),
+	"ES": postal(`^((?:0[1-9]|[1-4]\d|5[0-2])\d{3})$`, "${1}", "28001"),
+	"FI": postal(`^(\d{5})$`, "${1}", "00100"),
+	"FO": postal(`^(\d{3})$`, "FO-${1}", "FO-100"),
+	"FR": postal(`^(\d{5})$`, "${1}", "75001"),
+	"GB": postalGB,
+	"GG": postalGB,
+	"GL": postal(`^(39\d{2})$`, "${1}", "3900"),
+	"GR": postal(`^(\d{3}) ?(\d{2})$`, "${1} ${2}", "105 57"),
+	"GU": postalUS,
+	"HR": postal(`^(\d{5})$`, "${1}", "10000"),
+	"HU": postal(`^(\d{4})$`, "${1}", "1011"),
+	"IE": postal(`^([AC-FHKNPRTV-Y]\d{2}|D6W) ?([0-9AC-FHKNPRTV-Y]{4})$`, "${1} ${2}", "D02 X285"),
+	"IL": postal(`^(\d{7})$`, "${1}", "9614303"),
+	"IM": postalGB,
+	"IN": postal(`^(\d{3}) ?(\d{3})$`, "${1}${2}", "110001"),
+	"IS": postal(`^(\d{3})$`, "${1}", "101"),
+	"IT": postal(`^(\d{5})$`, "${1}", "00118"),
+	"JE": postalGB,
+	"JP": postal(`^(\d{3})-?(\d{4})$`, "${1}-${2}", "100-0001"),
+	"KR": postal(`^(\d{5})$`, "${1}", "03187"),
+	"KZ": postal(`^(\d{6}|[A-Z]\d{2}[A-Z]\d[A-Z]\d)$`, "${1}", "010000"),
+	"LI": postal(`^(94(?:8[5-9]|9[0-8]))$`, "${1}", "9490"),
+	"LT": postal(`^(\d{5})$`, "LT-${1}", "LT-01100"),
+	"LU": postal(`^(?:L-?)?(\d{4})$`, "L-${1}", "L-1009"),
+	"LV": postal(`^(\d{4})$`, "LV-${1}", "LV-1010"),
+	"MC": postal(`^(980\d{2})$`, "${1}", "98000"),
+	"MP": postalUS,
+	"MT": postal(`^([A-Z]{3}) ?(\d{4})$`, "${1} ${2}", "VLT 1117"),
+	"MX": postal(`^(\d{5})$`, "${1}", "06000"),
+	"NL": postal(`^([1-9]\d{3}) ?([A-Z]{2})$`, "${1} ${2}", "1012 JS"),
+	"NO": postal(`^(\d{4})$`, "${1}", "0150"),
+	"NZ": postal(`^(\d{4})$`, "${1}", "6011"),
+	"PL": postal(`^(\d{2})-?(\d{3})$`, "${1}-${2}", "00-950"),
+	"PR": postalUS,
+	"PT": postal(`^(\d{4})-?(\d{3})$`, "${1}-${2}", "1000-001"),
+	"RO": postal(`^(\d{6})$`, "${1}", "010011"),
+	"RU": postal(`^(\d{6})$`, "${1}", "101000"),
+	"SE": postal(`^(\d{3}) ?(\d{2})$`, "${1} ${2}", "111 22"),
+	"SG": postal(`^(\d{6})$`, "${1}", "018956"),
+	"SI": postal(`^(\d{4})$`, "${1}", "1000"),
+	"SK": postal(`^(\d{3}) ?(\d{2})$`, "${1} ${2}", "811 01"),
+	"TR": postal(`^(\d{5})$`, "${1}", "06100"),
+	"TW": postal(`^(\d{3}(?:\d{2,3})?)$`, "${1}", "100"),
+	"UA": postal(`^(\d{5})$`, "${1}", "01001"),
+	"US": postalUS,
+	"VI": postalUS,
+	"ZA": postal(`^(\d{4})$`, "${1}", "0001"),
+}
+
+// Uppercases code and collapses its whitespace into single spaces.
+func normalizePostalCode(code string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(code)), " ")
+}
+
+// Returns postal code in the canonical format of country c, e.g. "111 22" for "11122" in Sweden,
+// and whether it is valid. A country prefix such as "SE-" may be included.
+// Postal codes of countries without a known format are only normalized for case and whitespace,
+// and are valid unless empty.
+func PostalCode(code string, c *country.Country) (string, bool) {
+	code = normalizePostalCode(code)
+	if code == "" || c == nil {
+		return "", false
+	}
+
+	iso2 := strings.ToUpper(c.ISO2)
+	f, ok := postalFormats[iso2]
+	if !ok {
+		return code, true
+	}
+
+	if !f.pattern.MatchString(code) {
+		code = strings.TrimLeft(strings.TrimPrefix(code, iso2), "- ")
+		if !f.pattern.MatchString(code) {
+			return "", false
+		}
+	}
+
+	return strings.TrimRight(f.pattern.ReplaceAllString(code, f.template), " -"), true
+}
+
+// National trunk prefixes dialled before national numbers, by alpha-2 code, where they differ from "0".
+// In Italy, San Marino and the Vatican the leading zero is part of the number.
+var trunkPrefixes = map[string]string{
+	"HU": "06",
+	"IT": "",
+	"KZ": "8",
+	"RU": "8",
+	"SM": "",
+	"VA": "",
+}
+
+// Characters allowed in phone numbers besides digits and a leading "+".
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "/", "", "(0)", "", "(", "", ")", "")
+
+// Returns phone number in E.164 format, e.g. "+46812345678" for "08-123 456 78" in Sweden.
+// Numbers in international format, starting with "+" or "00", are kept as they are. National numbers are prefixed with
+// the calling code of country c after the trunk prefix is removed, and require c to be known.
+// Returns an error if the number is not a valid phone number.
+func E164(number string, c *country.Country) (string, error) {
+	n := phoneSeparators.Replace(strings.TrimSpace(number))
+	m := c.Metadata()
+
+	cc := ""
+	if m != nil {
+		cc = m.CallingCode
+	}
+	nanp := strings.HasPrefix(cc, "1")
+
+	switch {
+	case strings.HasPrefix(n, "+"):
+		n = n[1:]
+	case strings.HasPrefix(n, "00"):
+		n = n[2:]
+	case nanp && strings.HasPrefix(n, "011"):
+		n = n[3:]
+	case cc == "":
+		return "", errors.New("Phone number is not in international format and the calling code of the delivery country is unknown.")
+	case nanp:
+		// Numbers of the North American Numbering Plan are ten digits, or seven within the area of a territory.
+		if len(n) == 11 && n[0] == '1' {
+			n = n[1:]
+		}
+		if len(n) == 7 && len(cc) == 4 {
+			n = cc[1:] + n
+		}
+		if len(n) != 10 {
+			return "", errors.New("Phone number is not a valid North American number.")
+		}
+		n = "1" + n
+	default:
+		trunk, ok := trunkPrefixes[strings.ToUpper(c.ISO2)]
+		if !ok {
+			trunk = "0"
+		}
+		n = cc + strings.TrimPrefix(n, trunk)
+	}
+
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return "", errors.New("Phone number contains characters other than digits.")
+		}
+	}
+
+	// E.164 numbers are at most 15 digits, the shortest in use are 7 digits.
+	if len(n) < 7 || len(n) > 15 {
+		return "", errors.New("Phone number has too few or too many digits.")
+	}
+
+	return "+" + n, nil
+}
@@ -0,0 +1,158 @@
+package printorder
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"testing"
+)
+
+func TestCanNormalizePostalCodes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		iso2     string
+		code     string
+		expected string
+		valid    bool
+	}{
+		{"SE", "11122", "111 22", true},
+		{"SE", "se-111 22", "111 22", true},
+		{"SE", "1112", "", false},
+		{"GB", "sw1a1aa", "SW1A 1AA", true},
+		{"GB", "M1  1AE", "M1 1AE", true},
+		{"GB", "12345", "", false},
+		{"US", "12345", "12345", true},
+		{"US", "123456789", "12345-6789", true},
+		{"US", "12345-678", "", false},
+		{"NL", "1012js", "1012 JS", true},
+		{"LV", "1010", "LV-1010", true},
+		{"LV", "LV-1010", "LV-1010", true},
+		{"CA", "k1a0b1", "K1A 0B1", true},
+		{"BO", "La Paz 1", "LA PAZ 1", true},
+	}
+
+	for _, v := range tests {
+		code, ok := PostalCode(v.code, country.ByISO2(v.iso2))
+		if code != v.expected || ok != v.valid {
+			t.Errorf(`%s "%s": Expected "%s" and %t but got "%s" and %t.`, v.iso2, v.code, v.expected, v.valid, code, ok)
+		}
+	}
+}
+
+func TestCanNormalizePhoneNumbersToE164(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		iso2     string
+		number   string
+		expected string
+	}{
+		{"SE", "08-123 456 78", "+46812345678"},
+		{"SE", "+46 (0)8 123 456 78", "+46812345678"},
+		{"SE", "0046 8 123 456 78", "+46812345678"},
+		{"NO", "+47 22 12 34 56", "+4722123456"},
+		{"GB", "020 7946 0018", "+442079460018"},
+		{"IT", "06 1234 5678", "+390612345678"},
+		{"US", "(212) 555-0123", "+12125550123"},
+		{"US", "1 212 555 0123", "+12125550123"},
+		{"JM", "555-0123", "+18765550123"},
+		{"RU", "8 912 345 67 89", "+79123456789"},
+	}
+
+	for _, v := range tests {
+		got, err := E164(v.number, country.ByISO2(v.iso2))
+		if err != nil || got != v.expected {
+			t.Errorf(`%s "%s": Expected "%s" but got "%s" and error: %v`, v.iso2, v.number, v.expected, got, err)
+		}
+	}
+
+	invalid := map[string]*country.Country{
+		"08-123 45 ext 6": country.ByISO2("SE"),
+		"123":             country.ByISO2("SE"),
+		"555-0123":        country.ByISO2("US"),
+		"08-123 456 78":   nil,
+	}
+
+	for number, c := range invalid {
+		if got, err := E164(number, c); err == nil {
+			t.Errorf(`"%s": Expected an error but got "%s".`, number, got)
+		}
+	}
+}
+
+func TestCanValidateAddress(t *testing.T) {
+	t.Parallel()
+	p := &PrintOrder{
+		ID:              1,
+		DeliveryStreet:  "Storgatan 1",
+		DeliveryZip:     "11122",
+		DeliveryCity:    "Stockholm",
+		DeliveryPhone:   "070-123 45 67",
+		DeliveryCountry: &country.Country{ID: 205, ISO2: "SE", Name: "Sweden"},
+	}
+
+	v := p.ValidateAddress()
+	if !v.Valid() || v.Err() != nil {
+		t.Errorf("Expected valid address but got: %v", v.Err())
+	}
+	if v.PostalCode != "111 22" || v.Phone != "+46701234567" || v.Country.ISO2 != "SE" {
+		t.Errorf("Expected normalized address but got: %+v", v)
+	}
+
+	// Postal codes are not used in Hong Kong.
+	p = &PrintOrder{DeliveryStreet: "1 Queen's Road", DeliveryCity: "Hong Kong", DeliveryCountry: &country.Country{ISO2: "HK"}}
+	if v := p.ValidateAddress(); !v.Valid() {
+		t.Errorf("Expected address without postal code to be valid but got: %v", v.Err())
+	}
+}
+
+func TestValidateAddressReportsFieldProblems(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		p        *PrintOrder
+		problems map[string]string
+	}{
+		"Missing fields": {
+			&PrintOrder{DeliveryCountry: &country.Country{ISO2: "SE"}},
+			map[string]string{DELIVERY_STREET: ADDRESS_MISSING, DELIVERY_CITY: ADDRESS_MISSING, DELIVERY_ZIP: ADDRESS_MISSING},
+		},
+		"Invalid fields": {
+			&PrintOrder{DeliveryStreet: "1 Main St", DeliveryCity: "London", DeliveryZip: "12345", DeliveryPhone: "12", DeliveryCountry: &country.Country{ISO2: "GB"}},
+			map[string]string{DELIVERY_ZIP: ADDRESS_INVALID, DELIVERY_PHONE: ADDRESS_INVALID},
+		},
+		"Unknown country": {
+			&PrintOrder{DeliveryStreet: "Storgatan 1", DeliveryCity: "Stockholm", DeliveryZip: "11122", DeliveryCountryId: "999999"},
+			map[string]string{DELIVERY_COUNTRY_ID: ADDRESS_UNKNOWN_COUNTRY},
+		},
+	}
+
+	for name, test := range tests {
+		v := test.p.ValidateAddress()
+		if len(v.Problems) != len(test.problems) {
+			t.Errorf("%s: Expected %d problems but got: %v", name, len(test.problems), v.Problems)
+		}
+		for _, p := range v.Problems {
+			if test.problems[p.Field] != p.Code {
+				t.Errorf(`%s: Unexpected problem "%s" with %s.`, name, p.Code, p.Field)
+			}
+		}
+	}
+}
+
+func TestCanValidateAddressesOfIndexPage(t *testing.T) {
+	t.Parallel()
+	se := &country.Country{ISO2: "SE"}
+	r := &IndexResponse{Data: []PrintOrder{
+		{ID: 1, DeliveryStreet: "Storgatan 1", DeliveryCity: "Stockholm", DeliveryZip: "111 22", DeliveryCountry: se},
+		{ID: 2, DeliveryStreet: "Storgatan 2", DeliveryCity: "Stockholm", DeliveryZip: "1112", DeliveryCountry: se},
+		{ID: 3, DeliveryCity: "Stockholm", DeliveryZip: "111 22", DeliveryCountry: se},
+	}}
+
+	report := r.ValidateAddresses()
+	if report.Checked != 3 || len(report.Invalid) != 2 {
+		t.Fatalf("Expected 2 of 3 addresses to be invalid but got: %+v", report)
+	}
+	if report.Invalid[0].PrintOrderID != 2 || report.Invalid[1].PrintOrderID != 3 {
+		t.Errorf("Expected invalid print orders 2 and 3 but got %d and %d.", report.Invalid[0].PrintOrderID, report.Invalid[1].PrintOrderID)
+	}
+	if c := report.Counts(); c[ADDRESS_INVALID] != 1 || c[ADDRESS_MISSING] != 1 {
+		t.Errorf("Expected one invalid and one missing field but got: %v", c)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorder
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"strings"
+	"unicode"
+)
+
+// AddressStyle defines how FormatAddress formats address labels.
+type AddressStyle struct {
+	// Alpha-2 code of the country items are sent from, e.g. "SE". The country line is left out of addresses in it.
+	Origin string
+	// Uppercases all lines, as required by some carriers.
+	Uppercase bool
+	// Transliterates characters outside Latin-1, e.g. "ł" to "l" and "Ж" to "Zh", for carriers that only accept Latin-1.
+	Latin1 bool
+}
+
+// Address template of a country in the style of UPU S42 and libaddressinput.
+// Lines are separated by %n and fields are %N (recipient name), %O (company name), %A (street), %C (city),
+// %S (state, province or county) and %Z (postal code). Fields listed in upper are uppercased.
+type addressTemplate struct {
+	format string
+	upper  string
+}
+
+// Address templates.
+var (
+	templateDefault = addressTemplate{"%N%n%O%n%A%n%C %Z", "C"}
+	// Postal code before city, e.g. "111 22 STOCKHOLM".
+	templateZipCity = addressTemplate{"%N%n%O%n%A%n%Z %C", "C"}
+	// Company before recipient and postal code before city.
+	templateCompanyZipCity = addressTemplate{"%O%n%N%n%A%n%Z %C", "C"}
+	// City and postal code on separate lines, e.g. "LONDON" and "SW1A 1AA".
+	templateCityZipLines = addressTemplate{"%N%n%O%n%A%n%C%n%Z", "CZ"}
+	// City, state and postal code on one line, e.g. "ANYTOWN NY 12345-6789".
+	templateCityStateZip = addressTemplate{"%N%n%O%n%A%n%C %S %Z", "CS"}
+)
+
+// Address templates by alpha-2 code. Countries not listed use templateDefault.
+var addressTemplates = map[string]addressTemplate{
+	"AS": templateCityStateZip,
+	"AT": templateZipCity,
+	"AU": {"%O%n%N%n%A%n%C %S %Z", "CS"},
+	"AX": templateZipCity,
+	"BE": templateZipCity,
+	"BG": templateZipCity,
+	"CA": {"%N%n%O%n%A%n%C %S %Z", "NOACSZ"},
+	"CH": templateZipCity,
+	"CZ": templateZipCity,
+	"DE": templateZipCity,
+	"DK": templateZipCity,
+	"EE": templateZipCity,
+	"ES": templateZipCity,
+	"FI": templateZipCity,
+	"FO": templateZipCity,
+	"FR": {"%O%n%N%n%A%n%Z %C", "CZ"},
+	"GB": templateCityZipLines,
+	"GG": templateCityZipLines,
+	"GL": templateZipCity,
+	"GR": templateZipCity,
+	"GU": templateCityStateZip,
+	"HR": templateZipCity,
+	"IE": templateCityZipLines,
+	"IM": templateCityZipLines,
+	"IS": templateZipCity,
+	"IT": templateZipCity,
+	"JE": templateCityZipLines,
+	"LI": templateZipCity,
+	"LT": templateZipCity,
+	"LU": templateZipCity,
+	"LV": templateZipCity,
+	"MC": templateZipCity,
+	"MP": templateCityStateZip,
+	"NL": templateZipCity,
+	"NO": templateZipCity,
+	"PL": templateZipCity,
+	"PR": templateCityStateZip,
+	"PT": templateZipCity,
+	"RO": templateZipCity,
+	"SE": templateCompanyZipCity,
+	"SI": templateZipCity,
+	"SK": templateZipCity,
+	"US": templateCityStateZip,
+	"VI": templateCityStateZip,
+}
+
+// Names of countries on address labels, by alpha-2 code, where they differ from the ISO 3166 name
+// without qualifiers.
+var labelCountryNames = map[string]string{
+	"BQ": "Caribbean Netherlands",
+	"CC": "Cocos Islands",
+	"CD": "Democratic Republic of the Congo",
+	"GB": "United Kingdom",
+	"KP": "North Korea",
+	"KR": "South Korea",
+	"RU": "Russia",
+	"SH": "Saint Helena",
+	"US": "United States",
+	"VG": "British Virgin Islands",
+	"VI": "United States Virgin Islands",
+}
+
+// Returns the lines of the address label of the PrintOrder, formatted according to the template of the delivery country.
+//
+// The recipient name and the company name are put on separate lines in the order of the country, and the recipient
+// name is left out if it is the same as the company name. The city line is uppercased as recommended by UPU S42,
+// as is the country line, which is added unless the delivery country is style.Origin. Where the template has a state,
+// as in the US, it is taken from the city if given after a comma, e.g. "Anytown, NY". Postal codes are formatted as by
+// PostalCode if they are valid. Empty lines are left out.
+func (p *PrintOrder) FormatAddress(style AddressStyle) []string {
+	c := p.deliveryCountry()
+
+	iso2 := ""
+	if c != nil {
+		iso2 = strings.ToUpper(c.ISO2)
+	}
+	t, ok := addressTemplates[iso2]
+	if !ok {
+		t = templateDefault
+	}
+
+	name := strings.TrimSpace(p.RecipientFirstname + " " + p.RecipientLastname)
+	company := p.RecipientCompanyName
+	if strings.EqualFold(normalizeLine(name), normalizeLine(company)) {
+		name = ""
+	}
+
+	city, state := p.DeliveryCity, ""
+	if strings.Contains(t.format, "%S") {
+		if i := strings.LastIndex(city, ","); i >= 0 {
+			city, state = city[:i], city[i+1:]
+		}
+	}
+
+	zip, ok := PostalCode(p.DeliveryZip, c)
+	if !ok {
+		zip = p.DeliveryZip
+	}
+
+	fields := map[byte]string{'N': name, 'O': company, 'C': city, 'S': state, 'Z': zip}
+	for k, v := range fields {
+		if strings.IndexByte(t.upper, k) >= 0 {
+			fields[k] = strings.ToUpper(v)
+		}
+	}
+
+	street := p.DeliveryStreet
+	if strings.IndexByte(t.upper, 'A') >= 0 {
+		street = strings.ToUpper(street)
+	}
+
+	var lines []string
+	for _, f := range strings.Split(t.format, "%n") {
+		if f == "%A" {
+			lines = append(lines, strings.Split(street, "\n")...)
+			continue
+		}
+
+		var b strings.Builder
+		for i := 0; i < len(f); i++ {
+			if f[i] == '%' && i+1 < len(f) {
+				b.WriteString(fields[f[i+1]])
+				i++
+				continue
+			}
+			b.WriteByte(f[i])
+		}
+		lines = append(lines, b.String())
+	}
+
+	if c != nil && !strings.EqualFold(c.ISO2, style.Origin) {
+		lines = append(lines, strings.ToUpper(labelCountryName(c)))
+	}
+
+	l := lines[:0]
+	for _, v := range lines {
+		v = normalizeLine(v)
+		if style.Uppercase {
+			v = strings.ToUpper(v)
+		}
+		if style.Latin1 {
+			v = Latin1(v)
+		}
+		if v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// Trims line and collapses its whitespace into single spaces.
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// Returns the name of country c on address labels, e.g. "Bolivia" for "Bolivia (Plurinational State of)".
+func labelCountryName(c *country.Country) string {
+	if n, ok := labelCountryNames[strings.ToUpper(c.ISO2)]; ok {
+		return n
+	}
+
+	n := c.Name
+	if i := strings.Index(n, " ("); i > 0 {
+		n = n[:i]
+	}
+	if i := strings.Index(n, ", "); i > 0 {
+		n = n[:i]
+	}
+	return n
+}
+
+// Letters and punctuation outside Latin-1 and what they are transliterated to.
+var latin1Letters = map[string]string{
+	"A":   "ĀĂĄǍẠẢẤẦẨẪẬẮẰẲẴẶ",
+	"a":   "āăąǎạảấầẩẫậắằẳẵặ",
+	"C":   "ĆĈĊČ",
+	"c":   "ćĉċč",
+	"D":   "ĎĐ",
+	"d":   "ďđ",
+	"E":   "ĒĔĖĘĚẸẺẼẾỀỂỄỆƏ",
+	"e":   "ēĕėęěẹẻẽếềểễệə",
+	"G":   "ĜĞĠĢ",
+	"g":   "ĝğġģ",
+	"H":   "ĤĦ",
+	"h":   "ĥħ",
+	"I":   "ĨĪĬĮİỈỊ",
+	"i":   "ĩīĭįıỉị",
+	"J":   "Ĵ",
+	"j":   "ĵ",
+	"K":   "Ķ",
+	"k":   "ķ",
+	"L":   "ĹĻĽĿŁ",
+	"l":   "ĺļľŀł",
+	"N":   "ŃŅŇ",
+	"n":   "ńņňŉ",
+	"O":   "ŌŎŐƠỌỎỐỒỔỖỘỚỜỞỠỢ",
+	"o":   "ōŏőơọỏốồổỗộớờởỡợ",
+	"R":   "ŔŖŘ",
+	"r":   "ŕŗř",
+	"S":   "ŚŜŞŠȘ",
+	"s":   "śŝşšș",
+	"T":   "ŢŤŦȚ",
+	"t":   "ţťŧț",
+	"U":   "ŨŪŬŮŰŲƯỤỦỨỪỬỮỰ",
+	"u":   "ũūŭůűųưụủứừửữự",
+	"W":   "Ŵ",
+	"w":   "ŵ",
+	"Y":   "ŶŸỲỴỶỸ",
+	"y":   "ŷỳỵỷỹ",
+	"Z":   "ŹŻŽ",
+	"z":   "źżž",
+	"OE":  "Œ",
+	"oe":  "œ",
+	"'":   "‘’‚′ʻʼ",
+	`"`:   "“”„″",
+	"-":   "‐‑‒–—−",
+	"...": "…",
+	"EUR": "€",
+}
+
+// Transliteration of lowercase Cyrillic letters. Uppercase letters are transliterated to capitalized Latin letters.
+var cyrillicLetters = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e", 'ё': "e", 'є': "ye", 'ж': "zh",
+	'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Transliteration of single runes.
+var latin1Table = func() map[rune]string {
+	m := map[rune]string{}
+	for to, from := range latin1Letters {
+		for _, r := range from {
+			m[r] = to
+		}
+	}
+	for from, to := range cyrillicLetters {
+		m[from] = to
+		if to != "" {
+			m[unicode.ToUpper(from)] = strings.ToUpper(to[:1]) + to[1:]
+		} else {
+			m[unicode.ToUpper(from)] = ""
+		}
+	}
+	return m
+}()
+
+// Transliterates the characters of s outside Latin-1, e.g. "Łódź" to "Lódz" and "Москва" to "Moskva".
+// Characters that cannot be transliterated are replaced by "?".
+func Latin1(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch to, ok := latin1Table[r]; {
+		case r <= unicode.MaxLatin1:
+			b.WriteRune(r)
+		case ok:
+			b.WriteString(to)
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks of decomposed letters.
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package printorder
+
+import (
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"reflect"
+	"testing"
+)
+
+func TestCanFormatAddressLabels(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		p        *PrintOrder
+		style    AddressStyle
+		expected []string
+	}{
+		"Domestic Sweden": {
+			&PrintOrder{RecipientFirstname: "Anna", RecipientLastname: "Svensson", RecipientCompanyName: "Förlaget AB", DeliveryStreet: "Storgatan 1", DeliveryZip: "11122", DeliveryCity: "Stockholm", DeliveryCountry: &country.Country{ISO2: "SE"}},
+			AddressStyle{Origin: "SE"},
+			[]string{"Förlaget AB", "Anna Svensson", "Storgatan 1", "111 22 STOCKHOLM"},
+		},
+		"United Kingdom": {
+			&PrintOrder{RecipientFirstname: "John", RecipientLastname: "Smith", DeliveryStreet: "Flat 2\n10 Downing Street", DeliveryZip: "sw1a2aa", DeliveryCity: "London", DeliveryCountry: &country.Country{ISO2: "GB"}},
+			AddressStyle{Origin: "SE"},
+			[]string{"John Smith", "Flat 2", "10 Downing Street", "LONDON", "SW1A 2AA", "UNITED KINGDOM"},
+		},
+		"United States": {
+			&PrintOrder{RecipientFirstname: "Jane", RecipientLastname: "Doe", RecipientCompanyName: "Books Inc", DeliveryStreet: "1 Main St", DeliveryZip: "123456789", DeliveryCity: "Anytown, NY", DeliveryCountry: &country.Country{ISO2: "US"}},
+			AddressStyle{Origin: "SE"},
+			[]string{"Jane Doe", "Books Inc", "1 Main St", "ANYTOWN NY 12345-6789", "UNITED STATES"},
+		},
+		"Company only": {
+			&PrintOrder{RecipientFirstname: "Books", RecipientLastname: "Inc", RecipientCompanyName: "Books  Inc", DeliveryStreet: "Hauptstraße 1", DeliveryZip: "10115", DeliveryCity: "Berlin", DeliveryCountry: &country.Country{ISO2: "DE"}},
+			AddressStyle{Origin: "SE", Uppercase: true},
+			[]string{"BOOKS INC", "HAUPTSTRAßE 1", "10115 BERLIN", "GERMANY"},
+		},
+		"Latin-1": {
+			&PrintOrder{RecipientFirstname: "Łukasz", RecipientLastname: "Wiśniewski", DeliveryStreet: "ul. Piotrkowska 1", DeliveryZip: "90001", DeliveryCity: "Łódź", DeliveryCountry: &country.Country{ISO2: "PL"}},
+			AddressStyle{Origin: "SE", Latin1: true},
+			[]string{"Lukasz Wisniewski", "ul. Piotrkowska 1", "90-001 LÓDZ", "POLAND"},
+		},
+		"Unknown country": {
+			&PrintOrder{RecipientFirstname: "Anna", DeliveryStreet: "Street 1", DeliveryZip: "123", DeliveryCity: "City"},
+			AddressStyle{Origin: "SE"},
+			[]string{"Anna", "Street 1", "CITY 123"},
+		},
+	}
+
+	for name, test := range tests {
+		if got := test.p.FormatAddress(test.style); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf(`%s: Expected "%q" but got "%q".`, name, test.expected, got)
+		}
+	}
+}
+
+func TestCanTransliterateToLatin1(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Łódź":            "Lódz",
+		"Москва":          "Moskva",
+		"Жуковский":       "Zhukovskiy",
+		"Hà Nội":          "Hà Noi",
+		"Ŝtono – “Ĉefo”":  `Stono - "Cefo"`,
+		"Åre":             "Åre",
+		"東京":              "??",
+	}
+
+	for s, expected := range tests {
+		if got := Latin1(s); got != expected {
+			t.Errorf(`"%s": Expected "%s" but got "%s".`, s, expected, got)
+		}
+	}
+}
@@ -5,14 +5,14 @@
 package printorder
 
 import (
-	"fmt"
+	"context"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
 	"github.com/publitsweden/ProductionAPIGoSDK/country"
 	"github.com/publitsweden/ProductionAPIGoSDK/printdata"
 	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
+	"iter"
 	"net/url"
-	"strings"
 )
 
 // With constants.
@@ -45,10 +45,13 @@ type Resource struct {
 // Endpoint enumeration type.
 type Endpoint int
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX: "print_orders",
-	SHOW:  "print_orders/%v",
+// Generic resource backing the package functions.
+var resource = production.NewResource[PrintOrder, IndexResponse]("print_orders")
+
+// Keeps recipient personal data out of logged bodies.
+func init() {
+	production.RegisterRedactedFields(RECIPIENT_FIRSTNAME, RECIPIENT_LASTNAME, RECIPIENT_COMPANY_NAME, DELIVERY_STREET,
+		DELIVERY_ZIP, DELIVERY_CITY, DELIVERY_PHONE, DELIVERY_MSG)
 }
 
 // PrintOrder attribute constants.
@@ -103,43 +106,74 @@ type PrintOrder struct {
 }
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
 
 // Returns PrintOrder from Publit API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*PrintOrder, error) {
-	po := &PrintOrder{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, po, queryParams...)
-	return po, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
 }
 
-// Index response object.
-type IndexResponse struct {
-	Count int          `json:"count"`
-	Next  string       `json:"next"`
-	Prev  string       `json:"prev"`
-	Data  []PrintOrder `json:"data"`
+// Returns PrintOrder from Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*PrintOrder, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
+// Index response object.
+type IndexResponse production.IndexResponse[[]PrintOrder]
+
 // Indexes PrintOrders from the Publit API.
 // Returns IndexResponse where data contains PrintOrder list.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
 }
 
-// Method to Resource that fullfils the Enpointer interface as stated in production.
-func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
+// Indexes PrintOrders from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
 
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+// Iterates all PrintOrders matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[PrintOrder, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all PrintOrders matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[PrintOrder, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]PrintOrder, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
 	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all PrintOrders matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) ([]PrintOrder, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all PrintOrders matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) ([]PrintOrder, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return l, err
+}
 
-	return end
+// Returns the Timeline of the loaded Statuses of the PrintOrder, see WITH_STATUSES.
+func (p *PrintOrder) Timeline() *printorderstatus.Timeline {
+	return printorderstatus.NewTimeline(p.Statuses)
+}
+
+// Method to Resource that fullfils the Enpointer interface as stated in production.
+func (r Resource) GetEndpoint() string {
+	if r.Endpoint == SHOW {
+		return resource.Endpoint(r.Id).GetEndpoint()
+	}
+	return resource.Endpoint(0).GetEndpoint()
 }
@@ -1,21 +1,24 @@
 package printorder
 
 import (
-	"testing"
-	"net/url"
+	"encoding/json"
 	"errors"
-	"reflect"
-	"github.com/publitsweden/ProductionAPIGoSDK"
+	"fmt"
 	"github.com/publitsweden/APIUtilityGoSDK/client"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
 	"log"
-	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
 )
 
 func TestCanShowPrintOrder(t *testing.T) {
 	t.Parallel()
 	id := 4
-	r := Resource{Endpoint:SHOW,Id:id}
+	r := Resource{Endpoint: SHOW, Id: id}
 
 	cb := func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
 		if endpoint.GetEndpoint() != r.GetEndpoint() {
@@ -30,8 +33,8 @@ func TestCanShowPrintOrder(t *testing.T) {
 
 	c := &MockProductionAPIClient{
 		ReturnError: false,
-		T: t,
-		GetCall: cb,
+		T:           t,
+		GetCall:     cb,
 	}
 
 	_, err := Show(c, id)
@@ -43,7 +46,7 @@ func TestCanShowPrintOrder(t *testing.T) {
 
 func TestCanIndexPrintOrders(t *testing.T) {
 	t.Parallel()
-	r := Resource{Endpoint:INDEX}
+	r := Resource{Endpoint: INDEX}
 
 	cb := func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
 		if endpoint.GetEndpoint() != r.GetEndpoint() {
@@ -63,8 +66,8 @@ func TestCanIndexPrintOrders(t *testing.T) {
 
 	c := &MockProductionAPIClient{
 		ReturnError: false,
-		T: t,
-		GetCall: cb,
+		T:           t,
+		GetCall:     cb,
 	}
 
 	qp := func(q url.Values) {}
@@ -75,14 +78,95 @@ func TestCanIndexPrintOrders(t *testing.T) {
 	}
 }
 
+func TestCanIterateAllPrintOrders(t *testing.T) {
+	t.Parallel()
+
+	cb := func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
+		q := url.Values{}
+		for _, v := range queryParams {
+			v(q)
+		}
+
+		ir := model.(*IndexResponse)
+		if q.Get("offset") == "" {
+			ir.Data = []PrintOrder{{ID: 1}, {ID: 2}}
+			ir.Next = "https://url.to.publit/production/v2.0/print_orders?offset=2"
+		} else {
+			ir.Data = []PrintOrder{{ID: 3}}
+		}
+	}
+
+	c := &MockProductionAPIClient{
+		T:       t,
+		GetCall: cb,
+	}
+
+	var ids []int
+	for po, err := range All(c) {
+		if err != nil {
+			t.Fatal("Got Error but did not expect one", err)
+		}
+		ids = append(ids, po.ID)
+	}
+
+	if !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Errorf(`Iterated print orders did not match expected. Got: "%v"`, ids)
+	}
+
+	pos, err := IndexAll(c, 2)
+	if err != production.ErrTooManyItems {
+		t.Errorf(`Expected ErrTooManyItems but got: "%v"`, err)
+	}
+
+	if len(pos) != 2 {
+		t.Errorf("Expected 2 print orders but got %d.", len(pos))
+	}
+}
+
+func TestRecipientPersonalDataIsRedacted(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal(&PrintOrder{
+		RecipientFirstname:   "Jane",
+		RecipientLastname:    "Doe",
+		RecipientCompanyName: "Doe AB",
+		DeliveryStreet:       "Storgatan 1",
+		DeliveryZip:          "11122",
+		DeliveryCity:         "Stockholm",
+		DeliveryPhone:        "0701234567",
+		DeliveryMsg:          "Leave at the door",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := production.Redact(string(b))
+	for _, v := range []string{"Jane", "Doe", "Storgatan", "11122", "Stockholm", "0701234567", "Leave at the door"} {
+		if strings.Contains(s, v) {
+			t.Errorf(`Expected "%s" to be redacted but got: %s`, v, s)
+		}
+	}
+}
+
+func TestCanGetTimelineOfPrintOrder(t *testing.T) {
+	t.Parallel()
+	p := &PrintOrder{Statuses: printorderstatus.StatusList{
+		{ID: 2, Status: printorderstatus.STATE_ACCEPTED.AsString(), CreatedAt: "2017-01-02 00:00:00"},
+		{ID: 1, Status: printorderstatus.STATE_EXPORTED.AsString(), CreatedAt: "2017-01-01 00:00:00"},
+	}}
+
+	if s := p.Timeline().Current(); s != printorderstatus.STATE_ACCEPTED {
+		t.Errorf("Expected accepted state but got %d.", s)
+	}
+}
+
 // Test helper Client Mock
 type MockProductionAPIClient struct {
 	ReturnError bool
-	T *testing.T
-	GetCall func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values))
+	T           *testing.T
+	GetCall     func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values))
 }
 
-func (c *MockProductionAPIClient) Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) (error) {
+func (c *MockProductionAPIClient) Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error {
 	if c.ReturnError {
 		return errors.New("Some error")
 	}
@@ -111,10 +195,10 @@ func Example() {
 	// Filter request to show only created delivery numbers after 2017-01-01 00:00:00.
 	filter := common.QueryAttr(
 		common.AttrQuery{
-			Name: CREATED_AT,
+			Name:  CREATED_AT,
 			Value: "2017-07-15 00:00:00",
 			Args: common.AttrArgs{
-				Operator: common.OPERATOR_GREATER_EQUAL,
+				Operator:   common.OPERATOR_GREATER_EQUAL,
 				Combinator: common.COMBINATOR_AND,
 			},
 		},
@@ -168,7 +252,7 @@ func ExampleShow() {
 	}
 
 	// If request to Publit could find PrintOrder for id. The below would output true.
-	fmt.Println(po.ID==id)
+	fmt.Println(po.ID == id)
 }
 
 func ExampleIndex() {
@@ -193,4 +277,4 @@ func ExampleIndex() {
 
 	// Prints out number of returned items in response.
 	fmt.Printf("Total matches: %d, number of items in list: %d\n", po.Count, len(po.Data))
-}
\ No newline at end of file
+}
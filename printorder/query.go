@@ -0,0 +1,77 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorder
+
+import (
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
+	"time"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, INTERMEDIATOR_REF, CLIENT_REF, DELIVERY_MSG, ORDER_WEIGHT, BULKY,
+	RECIPIENT_FIRSTNAME, RECIPIENT_LASTNAME, RECIPIENT_COMPANY_NAME,
+	DELIVERY_STREET, DELIVERY_ZIP, DELIVERY_CITY, DELIVERY_PHONE, DELIVERY_COUNTRY_ID, DELIVERY_PRE_PAID,
+	STATUS, ACTIVE, EXPECTED_SHIP_DATE, CREATED_AT, UPDATED_AT,
+}
+
+// Relations that can be loaded with the response.
+var relations []string = []string{
+	WITH_STATUSES, WITH_PRINT_DATA, WITH_PRINT_DATA_FILE,
+	WITH_PRINT_DATA_MANIFESTATION, WITH_PRINT_DATA_MANIFESTATION_ISBN,
+	WITH_PRINT_DATA_PRINT_ITEM_PAPER, WITH_PRINT_DATA_PRINT_ITEM, WITH_PRINT_DATA_BOOK_BINDING,
+	WITH_DELIVERY_COUNTRY,
+}
+
+// QueryBuilder builds query parameters for indexing PrintOrders.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for PrintOrders.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, relations)
+	return q
+}
+
+// Filters on PrintOrders created at or after t.
+func (q *QueryBuilder) CreatedAfter(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_GREATER_EQUAL, t)
+}
+
+// Filters on PrintOrders created before t.
+func (q *QueryBuilder) CreatedBefore(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_LESS, t)
+}
+
+// Filters on PrintOrders updated at or after t.
+func (q *QueryBuilder) UpdatedAfter(t time.Time) *QueryBuilder {
+	return q.WhereTime(UPDATED_AT, production.OPERATOR_GREATER_EQUAL, t)
+}
+
+// Filters on PrintOrders updated before t.
+func (q *QueryBuilder) UpdatedBefore(t time.Time) *QueryBuilder {
+	return q.WhereTime(UPDATED_AT, production.OPERATOR_LESS, t)
+}
+
+// Filters on PrintOrders with state.
+func (q *QueryBuilder) Status(state printorderstatus.State) *QueryBuilder {
+	if state.AsString() == "" {
+		return q.AddError(fmt.Errorf(`Unknown state: "%d".`, state))
+	}
+	return q.Where(STATUS, production.OPERATOR_EQUAL, state.AsString())
+}
+
+// Filters on PrintOrders with client order reference.
+func (q *QueryBuilder) ClientRef(ref string) *QueryBuilder {
+	return q.Where(CLIENT_REF, production.OPERATOR_EQUAL, ref)
+}
+
+// Filters on PrintOrders with intermediator order reference.
+func (q *QueryBuilder) IntermediatorRef(ref string) *QueryBuilder {
+	return q.Where(INTERMEDIATOR_REF, production.OPERATOR_EQUAL, ref)
+}
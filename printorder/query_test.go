@@ -0,0 +1,62 @@
+package printorder
+
+import (
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCanBuildPrintOrderQuery(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	params, err := Query().
+		CreatedAfter(created).
+		Status(printorderstatus.STATE_EXPORTED).
+		With(WITH_STATUSES).
+		OrderByDesc(ID).
+		Limit(50).
+		Build()
+
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	got := url.Values{}
+	for _, v := range params {
+		v(got)
+	}
+
+	expected := url.Values{}
+	common.QueryAttr(common.AttrQuery{
+		Name:  CREATED_AT,
+		Value: "2017-01-01 00:00:00",
+		Args:  common.AttrArgs{Operator: string(production.OPERATOR_GREATER_EQUAL), Combinator: production.COMBINATOR_AND},
+	})(expected)
+	common.QueryAttr(common.AttrQuery{
+		Name:  STATUS,
+		Value: printorderstatus.STATE_EXPORTED.AsString(),
+		Args:  common.AttrArgs{Operator: string(production.OPERATOR_EQUAL), Combinator: production.COMBINATOR_AND},
+	})(expected)
+	common.QueryOrderBy([]string{ID}, production.ORDER_DIR_DESC)(expected)
+	common.QueryWith(WITH_STATUSES)(expected)
+	common.QueryLimit(50, 0)(expected)
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf(`Built query did not match expected. Got: "%v", expected: "%v"`, got.Encode(), expected.Encode())
+	}
+}
+
+func TestPrintOrderQueryRejectsUnknownState(t *testing.T) {
+	t.Parallel()
+
+	_, err := Query().Status(printorderstatus.State(100)).Build()
+
+	if err == nil {
+		t.Error("Did not receive an error but was expecting one.")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorderstatus
+
+import (
+	"context"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+)
+
+// Default number of workers of StoreBatch.
+const BATCH_WORKERS = 5
+
+// BatchOptions configures StoreBatch.
+type BatchOptions struct {
+	// Number of print orders handled concurrently. Defaults to BATCH_WORKERS.
+	Workers int
+	// Reports what would be posted without posting anything.
+	DryRun bool
+	// Refuses statuses that are not allowed to follow the state of the print order, see CanTransition.
+	Strict bool
+}
+
+// BatchResult is the outcome of StoreBatch for a print order.
+type BatchResult struct {
+	PrintOrderId int
+	// Statuses that were posted, or would have been posted in a dry run.
+	Posted StatusList
+	// Statuses that were not posted as the print order already is in their state, see StoreBatchContext.
+	Skipped StatusList
+	// Error stopping the statuses of the print order from being posted. Statuses after the failing one are not posted.
+	Err error
+}
+
+// Posts statuses for many print orders. Uses worker concurrency pattern.
+// See StoreBatchContext.
+func StoreBatch(c ProductionAPIGetPoster, l StatusList, opts BatchOptions) map[int]*BatchResult {
+	return StoreBatchContext(context.Background(), getPosterWithContext{production.GetterWithContext(c), production.PosterWithContext(c)}, l, opts)
+}
+
+// Posts statuses for many print orders. Uses worker concurrency pattern.
+// Print orders are handled concurrently, and the statuses of each print order in the order given. The last status of
+// each print order is read first, and statuses in the same state as the last one are skipped, so that running a batch
+// twice posts nothing the second time. Partially sent may follow itself for every parcel sent, so Partially sent
+// statuses are only skipped if their message is the same as well.
+// Posts are only retried if ctx is marked with production.AllowRetry.
+// Returns a map of results indexed on PrintOrderId. Print orders not yet handled when ctx is cancelled get the
+// context error as Err.
+func StoreBatchContext(ctx context.Context, c ProductionAPIContextGetPoster, l StatusList, opts BatchOptions) map[int]*BatchResult {
+	// Group statuses by print order, keeping their order.
+	var orders []int
+	byOrder := map[int]StatusList{}
+	for _, v := range l {
+		if v == nil {
+			continue
+		}
+		if _, ok := byOrder[v.PrintOrderId]; !ok {
+			orders = append(orders, v.PrintOrderId)
+		}
+		byOrder[v.PrintOrderId] = append(byOrder[v.PrintOrderId], v)
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = BATCH_WORKERS
+	}
+
+	jobs := make(chan StatusList, len(orders))
+	results := make(chan *BatchResult, len(orders))
+
+	// Create workers.
+	for i := 0; i < workers; i++ {
+		go batchWorker(ctx, c, opts, jobs, results)
+	}
+
+	// Add jobs to channel.
+	for _, id := range orders {
+		jobs <- byOrder[id]
+	}
+	// Close channel to indicate all jobs have been pushed.
+	close(jobs)
+
+	// Range results to be sure to wait until all workers have completed their task.
+	m := make(map[int]*BatchResult, len(orders))
+	for range orders {
+		r := <-results
+		m[r.PrintOrderId] = r
+	}
+
+	return m
+}
+
+// Worker designated for posting the statuses of print orders.
+func batchWorker(ctx context.Context, c ProductionAPIContextGetPoster, opts BatchOptions, jobs <-chan StatusList, results chan<- *BatchResult) {
+	for l := range jobs {
+		r := &BatchResult{PrintOrderId: l[0].PrintOrderId}
+		r.Err = ctx.Err()
+		if r.Err == nil {
+			r.Err = storeOrderStatuses(ctx, c, opts, l, r)
+		}
+		results <- r
+	}
+}
+
+// Posts the statuses l of a print order, recording the outcome in r.
+func storeOrderStatuses(ctx context.Context, c ProductionAPIContextGetPoster, opts BatchOptions, l StatusList, r *BatchResult) error {
+	last, err := lastStatusContext(ctx, c, r.PrintOrderId)
+	if err != nil {
+		return err
+	}
+
+	current, message := STATE_NONE, ""
+	if last != nil {
+		// Unknown states of the last status are treated as no state, and only matter for strict batches.
+		current, _ = ParseState(last.Status)
+		message = last.Message
+	}
+
+	for _, s := range l {
+		state, err := s.State()
+		if err != nil {
+			return err
+		}
+
+		if state == current && (state != STATE_PARTIALLY_SENT || s.Message == message) {
+			r.Skipped = append(r.Skipped, s)
+			continue
+		}
+
+		if opts.Strict && !CanTransition(current, state) {
+			return &TransitionError{PrintOrderID: r.PrintOrderId, From: current, To: state}
+		}
+
+		if !opts.DryRun {
+			if err := s.StoreContext(ctx, c); err != nil {
+				return err
+			}
+		}
+
+		r.Posted = append(r.Posted, s)
+		current, message = state, s.Message
+	}
+
+	return nil
+}
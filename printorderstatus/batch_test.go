@@ -0,0 +1,131 @@
+package printorderstatus
+
+import (
+	"errors"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCanStoreBatch(t *testing.T) {
+	t.Parallel()
+	c := &BatchAPIClient{Statuses: map[int]StatusList{
+		1: {{ID: 1, Status: STATE_ACCEPTED.AsString(), CreatedAt: "2017-01-01 00:00:00"}},
+		2: {{ID: 2, Status: STATE_IN_PRODUCTION.AsString(), CreatedAt: "2017-01-01 00:00:00"}},
+		3: {{ID: 3, Status: STATE_ABORTED.AsString(), CreatedAt: "2017-01-01 00:00:00"}},
+	}}
+
+	l := StatusList{
+		New(STATE_IN_PRODUCTION, 1, ""),
+		New(STATE_IN_PRODUCTION, 2, ""),
+		New(STATE_SENT, 1, ""),
+		New(STATE_SENT, 3, ""),
+	}
+
+	results := StoreBatch(c, l, BatchOptions{Workers: 2, Strict: true})
+
+	if len(results) != 3 {
+		t.Fatalf("Expected results for 3 print orders but got %d.", len(results))
+	}
+	if r := results[1]; r.Err != nil || len(r.Posted) != 2 || r.Posted[1].Status != STATE_SENT.AsString() {
+		t.Errorf("Expected both statuses of print order 1 to be posted in order but got: %+v", r)
+	}
+	if r := results[2]; r.Err != nil || len(r.Posted) != 0 || len(r.Skipped) != 1 {
+		t.Errorf("Expected status identical to the current state to be skipped but got: %+v", r)
+	}
+	if r := results[3]; !errors.Is(r.Err, ErrIllegalTransition) || len(r.Posted) != 0 {
+		t.Errorf("Expected illegal transition to be refused but got: %+v", r)
+	}
+	if n := len(c.Posted()); n != 2 {
+		t.Errorf("Expected 2 posted statuses but got %d.", n)
+	}
+
+	// Running the batch again posts nothing.
+	StoreBatch(c, StatusList{New(STATE_SENT, 1, "")}, BatchOptions{})
+	if n := len(c.Posted()); n != 2 {
+		t.Errorf("Expected no more posted statuses but got %d.", n-2)
+	}
+}
+
+func TestStoreBatchSkipsStatusesInTheCurrentState(t *testing.T) {
+	t.Parallel()
+	c := &BatchAPIClient{Statuses: map[int]StatusList{
+		1: {{ID: 1, Status: STATE_IN_PRODUCTION.AsString(), Message: "Printing", CreatedAt: "2017-01-01 00:00:00"}},
+		2: {{ID: 2, Status: STATE_PARTIALLY_SENT.AsString(), Message: "Parcel 1", CreatedAt: "2017-01-01 00:00:00"}},
+	}}
+
+	l := StatusList{
+		New(STATE_IN_PRODUCTION, 1, "Binding"),
+		New(STATE_PARTIALLY_SENT, 2, "Parcel 1"),
+		New(STATE_PARTIALLY_SENT, 2, "Parcel 2"),
+	}
+
+	results := StoreBatch(c, l, BatchOptions{})
+
+	if r := results[1]; r.Err != nil || len(r.Posted) != 0 || len(r.Skipped) != 1 {
+		t.Errorf("Expected status in the current state to be skipped regardless of message but got: %+v", r)
+	}
+	if r := results[2]; r.Err != nil || len(r.Posted) != 1 || r.Posted[0].Message != "Parcel 2" || len(r.Skipped) != 1 {
+		t.Errorf("Expected only the partially sent status with a new message to be posted but got: %+v", r)
+	}
+}
+
+func TestStoreBatchDryRunPostsNothing(t *testing.T) {
+	t.Parallel()
+	c := &BatchAPIClient{}
+
+	results := StoreBatch(c, StatusList{New(STATE_ACCEPTED, 1, ""), New(STATE_IN_PRODUCTION, 1, "")}, BatchOptions{DryRun: true})
+
+	if r := results[1]; r.Err != nil || len(r.Posted) != 2 {
+		t.Errorf("Expected statuses that would be posted to be reported but got: %+v", r)
+	}
+	if n := len(c.Posted()); n != 0 {
+		t.Errorf("Expected nothing to be posted but got %d statuses.", n)
+	}
+}
+
+// Concurrency safe client mock keeping posted statuses.
+type BatchAPIClient struct {
+	mu       sync.Mutex
+	Statuses map[int]StatusList
+	posted   StatusList
+}
+
+func (c *BatchAPIClient) Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error {
+	q := url.Values{}
+	for _, v := range queryParams {
+		v(q)
+	}
+	value, _, _ := strings.Cut(q.Get(PRINT_ORDER_ID), "[")
+	id, _ := strconv.Atoi(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	model.(*IndexResponse).Data = append(StatusList(nil), c.Statuses[id]...)
+	return nil
+}
+
+func (c *BatchAPIClient) Post(endpoint production.Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := *payload.(*Status)
+	s.ID = 100 + len(c.posted)
+	s.CreatedAt = "2017-02-01 00:00:00"
+	if c.Statuses == nil {
+		c.Statuses = map[int]StatusList{}
+	}
+	c.Statuses[s.PrintOrderId] = append(c.Statuses[s.PrintOrderId], &s)
+	c.posted = append(c.posted, &s)
+	return nil
+}
+
+func (c *BatchAPIClient) Posted() StatusList {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append(StatusList(nil), c.posted...)
+}
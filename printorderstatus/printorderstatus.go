@@ -9,14 +9,13 @@
 package printorderstatus
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"github.com/publitsweden/ProductionAPIGoSDK"
-	"net/http"
+	"iter"
 	"net/url"
 	"sort"
-	"strings"
 )
 
 // General constants for status.
@@ -77,14 +76,16 @@ var statues map[State]string = map[State]string{
 }
 
 // ProductionAPIGetter defines how the client should perform GET calls.
-type ProductionAPIGetter interface {
-	Get(endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) error
-}
+type ProductionAPIGetter = production.Getter
+
+// ProductionAPIContextGetter defines how the client should perform context aware GET calls.
+type ProductionAPIContextGetter = production.ContextGetter
 
 // ProductionAPIPoster defines how the client should perform POST calls.
-type ProductionAPIPoster interface {
-	Post(endpoint production.Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error
-}
+type ProductionAPIPoster = production.Poster
+
+// ProductionAPIContextPoster defines how the client should perform context aware POST calls.
+type ProductionAPIContextPoster = production.ContextPoster
 
 // Resource struct
 type Resource struct {
@@ -102,12 +103,8 @@ const (
 	POST
 )
 
-// Endpoints.
-var endpoints map[Endpoint]string = map[Endpoint]string{
-	INDEX: "print_order_statuses",
-	SHOW:  "print_order_statuses/%v",
-	POST:  "print_order_statuses",
-}
+// Generic resource backing the package functions.
+var resource = production.NewResource[Status, IndexResponse]("print_order_statuses")
 
 // Creates new status.
 func New(state State, PrintOrderId int, message string) *Status {
@@ -129,38 +126,84 @@ type StatusList []*Status
 
 // Sets status.
 func (s *Status) Store(c ProductionAPIPoster) error {
+	return s.StoreContext(context.Background(), production.PosterWithContext(c))
+}
+
+// Sets status.
+// The request is aborted if ctx is cancelled or its deadline expires.
+// As a POST, the call is not retried unless ctx is marked with production.AllowRetry, see StoreWithRetryContext.
+func (s *Status) StoreContext(ctx context.Context, c ProductionAPIContextPoster) error {
 	if s.ID != 0 {
 		return errors.New("Can not create new status for an existing one. (ID is set).")
 	}
-	r := Resource{Endpoint: POST}
-	pr := &StatusList{s}
-	err := c.Post(r, s, pr)
-	return err
+	return resource.Create(ctx, c, s)
+}
+
+// Sets status, opting in to the retry policy of the production.APIClient.
+// See StoreWithRetryContext.
+func (s *Status) StoreWithRetry(c ProductionAPIPoster) error {
+	return s.StoreWithRetryContext(context.Background(), production.PosterWithContext(c))
+}
+
+// Sets status, opting in to the retry policy of the production.APIClient.
+// The request is aborted if ctx is cancelled or its deadline expires.
+// A retried post may store the status twice, if an attempt was stored but its response was lost.
+func (s *Status) StoreWithRetryContext(ctx context.Context, c ProductionAPIContextPoster) error {
+	return s.StoreContext(production.AllowRetry(ctx), c)
 }
 
 // Returns Status from Publits production API.
 func Show(c ProductionAPIGetter, id int, queryParams ...func(q url.Values)) (*Status, error) {
-	po := &Status{}
-	r := Resource{Endpoint: SHOW, Id: id}
-	err := c.Get(r, po, queryParams...)
-	return po, err
+	return ShowContext(context.Background(), production.GetterWithContext(c), id, queryParams...)
 }
 
-// Index response object.
-type IndexResponse struct {
-	Count int        `json:"count"`
-	Next  string     `json:"next"`
-	Prev  string     `json:"prev"`
-	Data  StatusList `json:"data"`
+// Returns Status from Publits production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func ShowContext(ctx context.Context, c ProductionAPIContextGetter, id int, queryParams ...func(q url.Values)) (*Status, error) {
+	return resource.Show(ctx, c, id, queryParams...)
 }
 
+// Index response object.
+type IndexResponse production.IndexResponse[StatusList]
+
 // Indexes Statuses from the Publit API.
 // Returns IndexResponse where data contains StatusList.
 func Index(c ProductionAPIGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
-	ir := &IndexResponse{}
-	r := Resource{Endpoint: INDEX}
-	err := c.Get(r, ir, queryParams...)
-	return ir, err
+	return IndexContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Indexes Statuses from the Publit API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func IndexContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) (*IndexResponse, error) {
+	return resource.Index(ctx, c, queryParams...)
+}
+
+// Iterates all Statuses matching queryParams from the Publit API, following the pages of the index.
+func All(c ProductionAPIGetter, queryParams ...func(q url.Values)) iter.Seq2[*Status, error] {
+	return AllContext(context.Background(), production.GetterWithContext(c), queryParams...)
+}
+
+// Iterates all Statuses matching queryParams from the Publit API, following the pages of the index.
+// Iteration stops with the context error if ctx is cancelled.
+func AllContext(ctx context.Context, c ProductionAPIContextGetter, queryParams ...func(q url.Values)) iter.Seq2[*Status, error] {
+	fetch := func(ctx context.Context, queryParams ...func(q url.Values)) ([]*Status, string, error) {
+		ir, err := IndexContext(ctx, c, queryParams...)
+		return ir.Data, ir.Next, err
+	}
+	return production.Paginate(ctx, fetch, queryParams...)
+}
+
+// Collects all Statuses matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAll(c ProductionAPIGetter, max int, queryParams ...func(q url.Values)) (StatusList, error) {
+	return IndexAllContext(context.Background(), production.GetterWithContext(c), max, queryParams...)
+}
+
+// Collects all Statuses matching queryParams from the Publit API.
+// At most max items are collected, see production.Collect.
+func IndexAllContext(ctx context.Context, c ProductionAPIContextGetter, max int, queryParams ...func(q url.Values)) (StatusList, error) {
+	l, err := production.Collect(AllContext(ctx, c, queryParams...), max)
+	return StatusList(l), err
 }
 
 // Returns state as readable string as defined by the Publit APIs.
@@ -170,17 +213,14 @@ func (s State) AsString() string {
 
 // Method to Resource that fullfils the Enpointer interface as stated in production.
 func (r Resource) GetEndpoint() string {
-	e := endpoints[r.Endpoint]
-
-	end := e
-	if strings.Contains(e, "%v") && r.Id != 0 {
-		end = fmt.Sprintf(e, r.Id)
+	if r.Endpoint == SHOW {
+		return resource.Endpoint(r.Id).GetEndpoint()
 	}
-
-	return end
+	return resource.Endpoint(0).GetEndpoint()
 }
 
 // Retrieves last reported status from StatusList.
+// The list is sorted in place and must not be empty. See NewTimeline for an alternative that does neither.
 func (l StatusList) GetLast() *Status {
 	sort.Sort(l)
 	return l[len(l)-1]
@@ -0,0 +1,51 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorderstatus
+
+import (
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"strconv"
+	"time"
+)
+
+// Attributes that can be filtered and ordered on.
+var attributes []string = []string{
+	ID, PRINT_ORDER_ID, SENDER_TYPE, STATUS, MESSAGE, CREATED_AT, UPDATED_AT,
+}
+
+// QueryBuilder builds query parameters for indexing Statuses.
+type QueryBuilder struct {
+	*production.QueryBuilder[*QueryBuilder]
+}
+
+// Creates new QueryBuilder for Statuses.
+// Pass the result of Build to Index.
+func Query() *QueryBuilder {
+	q := &QueryBuilder{}
+	q.QueryBuilder = production.NewQueryBuilder(q, attributes, nil)
+	return q
+}
+
+// Filters on Statuses for print order.
+func (q *QueryBuilder) PrintOrderID(id int) *QueryBuilder {
+	return q.Where(PRINT_ORDER_ID, production.OPERATOR_EQUAL, strconv.Itoa(id))
+}
+
+// Filters on Statuses with state.
+func (q *QueryBuilder) Status(state State) *QueryBuilder {
+	if state.AsString() == "" {
+		return q.AddError(fmt.Errorf(`Unknown state: "%d".`, state))
+	}
+	return q.Where(STATUS, production.OPERATOR_EQUAL, state.AsString())
+}
+
+// Filters on Statuses created at or after t.
+func (q *QueryBuilder) CreatedAfter(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_GREATER_EQUAL, t)
+}
+
+// Filters on Statuses created before t.
+func (q *QueryBuilder) CreatedBefore(t time.Time) *QueryBuilder {
+	return q.WhereTime(CREATED_AT, production.OPERATOR_LESS, t)
+}
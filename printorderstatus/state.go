@@ -0,0 +1,89 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorderstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Returns the State of s, the inverse of AsString. Case and surrounding whitespace are ignored.
+func ParseState(s string) (State, error) {
+	t := strings.TrimSpace(s)
+	for k, v := range statues {
+		if strings.EqualFold(v, t) {
+			return k, nil
+		}
+	}
+	return STATE_NONE, fmt.Errorf(`Unknown state: "%s".`, s)
+}
+
+// MarshalText method to fulfil the encoding.TextMarshaler interface.
+// States are marshaled as defined by the Publit APIs, e.g. "In production". STATE_NONE is marshaled as an empty string.
+func (s State) MarshalText() ([]byte, error) {
+	if s == STATE_NONE {
+		return []byte{}, nil
+	}
+	if !s.IsValid() {
+		return nil, fmt.Errorf(`Unknown state: "%d".`, s)
+	}
+	return []byte(s.AsString()), nil
+}
+
+// UnmarshalText method to fulfil the encoding.TextUnmarshaler interface. An empty string is unmarshaled as STATE_NONE.
+func (s *State) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = STATE_NONE
+		return nil
+	}
+
+	v, err := ParseState(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// MarshalJSON method to fulfil the json.Marshaler interface. States are marshaled as strings, see MarshalText.
+func (s State) MarshalJSON() ([]byte, error) {
+	b, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON method to fulfil the json.Unmarshaler interface. Null is unmarshaled as STATE_NONE.
+func (s *State) UnmarshalJSON(b []byte) error {
+	var v *string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v == nil {
+		*s = STATE_NONE
+		return nil
+	}
+	return s.UnmarshalText([]byte(*v))
+}
+
+// Checks if the state is defined by the Publit APIs.
+func (s State) IsValid() bool {
+	return s.AsString() != ""
+}
+
+// Checks if the print order is done: delivered or aborted. Delivered print orders may still be returned.
+func (s State) IsTerminal() bool {
+	return s == STATE_DELIVERED || s == STATE_ABORTED
+}
+
+// Checks if the print order, or part of it, has left production: sent, partially sent or delivered.
+func (s State) IsShipped() bool {
+	return s == STATE_SENT || s == STATE_PARTIALLY_SENT || s == STATE_DELIVERED
+}
+
+// Returns the State of the status. Returns an error if the status is unknown.
+func (s *Status) State() (State, error) {
+	return ParseState(s.Status)
+}
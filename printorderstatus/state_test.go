@@ -0,0 +1,58 @@
+package printorderstatus
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanMarshalState(t *testing.T) {
+	t.Parallel()
+	type order struct {
+		State State `json:"state"`
+	}
+
+	b, err := json.Marshal(order{State: STATE_IN_PRODUCTION})
+	if err != nil || string(b) != `{"state":"In production"}` {
+		t.Errorf("Expected state to be marshaled as string but got %s and error: %v", b, err)
+	}
+
+	o := order{}
+	if err := json.Unmarshal([]byte(`{"state":"Partially sent"}`), &o); err != nil || o.State != STATE_PARTIALLY_SENT {
+		t.Errorf("Expected state to be unmarshaled but got %d and error: %v", o.State, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"state":"Lost"}`), &o); err == nil {
+		t.Error("Did not receive an error for unknown state but was expecting one.")
+	}
+
+	if _, err := json.Marshal(order{State: State(100)}); err == nil {
+		t.Error("Did not receive an error for unknown state but was expecting one.")
+	}
+
+	m := map[State]int{}
+	if err := json.Unmarshal([]byte(`{"Sent":2}`), &m); err != nil || m[STATE_SENT] != 2 {
+		t.Errorf("Expected states to be usable as map keys but got %v and error: %v", m, err)
+	}
+}
+
+func TestCanGetStateOfStatus(t *testing.T) {
+	t.Parallel()
+	s := New(STATE_DELIVERED, 1, "")
+
+	state, err := s.State()
+	if err != nil || state != STATE_DELIVERED {
+		t.Errorf("Expected delivered state but got %d and error: %v", state, err)
+	}
+	if !state.IsTerminal() || !state.IsShipped() {
+		t.Error("Expected delivered state to be terminal and shipped.")
+	}
+
+	if STATE_IN_PRODUCTION.IsTerminal() || STATE_IN_PRODUCTION.IsShipped() || !STATE_ABORTED.IsTerminal() {
+		t.Error("State predicates did not match expected.")
+	}
+
+	s.Status = "Lost"
+	if _, err := s.State(); err == nil {
+		t.Error("Did not receive an error but was expecting one.")
+	}
+}
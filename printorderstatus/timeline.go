@@ -0,0 +1,175 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorderstatus
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Timeline anomaly kinds.
+const (
+	// The status has neither a valid created_at nor updated_at and can not be placed in time.
+	ANOMALY_INVALID_TIME = "invalid_time"
+	// The status is not a known state.
+	ANOMALY_UNKNOWN_STATE = "unknown_state"
+	// The state follows the same state, e.g. Sent after Sent.
+	ANOMALY_DUPLICATE = "duplicate"
+	// The state can not follow the previous state, see CanTransition. E.g. Delivered after Accepted.
+	ANOMALY_OUT_OF_ORDER = "out_of_order"
+)
+
+// Anomaly is a status that does not fit in the timeline of a print order.
+type Anomaly struct {
+	Kind    string
+	Status  *Status
+	Message string
+}
+
+// StateChange is a change of the state of a print order.
+type StateChange struct {
+	From State
+	To   State
+	At   time.Time
+	// Status reporting the change.
+	Status *Status
+}
+
+// Timeline is the history of the states of a print order, built from its statuses.
+type Timeline struct {
+	statuses  StatusList
+	changes   []StateChange
+	anomalies []Anomaly
+	now       func() time.Time
+}
+
+// Returns the time of status s: its creation, or its last update if the creation time is missing.
+func statusTime(s *Status) (time.Time, bool) {
+	if t, err := s.CreatedAt.ConvertPublitTimeToTime(); err == nil && !t.IsZero() {
+		return t, true
+	}
+	if t, err := s.UpdatedAt.ConvertPublitTimeToTime(); err == nil && !t.IsZero() {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Creates new Timeline from the statuses of a print order, which are copied and not reordered.
+// Statuses are ordered by the time they were created, or updated if the creation time is missing, and then by ID.
+// Statuses that can not be placed in time or have unknown states are left out of the state changes, and reported
+// as anomalies along with statuses that do not follow the previous state according to CanTransition.
+func NewTimeline(l StatusList) *Timeline {
+	tl := &Timeline{now: time.Now}
+
+	times := make(map[*Status]time.Time, len(l))
+	for _, v := range l {
+		if v == nil {
+			continue
+		}
+		s := *v
+		t, ok := statusTime(&s)
+		if !ok {
+			tl.anomalies = append(tl.anomalies, Anomaly{Kind: ANOMALY_INVALID_TIME, Status: &s, Message: "Status has no valid time."})
+		}
+		times[&s] = t
+		tl.statuses = append(tl.statuses, &s)
+	}
+
+	sort.SliceStable(tl.statuses, func(i, j int) bool {
+		ti, tj := times[tl.statuses[i]], times[tl.statuses[j]]
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return tl.statuses[i].ID < tl.statuses[j].ID
+	})
+
+	current := STATE_NONE
+	for _, s := range tl.statuses {
+		t, ok := statusTime(s)
+		if !ok {
+			continue
+		}
+
+		state, err := ParseState(s.Status)
+		if err != nil {
+			tl.anomalies = append(tl.anomalies, Anomaly{Kind: ANOMALY_UNKNOWN_STATE, Status: s, Message: err.Error()})
+			continue
+		}
+
+		if !CanTransition(current, state) {
+			if state == current {
+				tl.anomalies = append(tl.anomalies, Anomaly{Kind: ANOMALY_DUPLICATE, Status: s, Message: fmt.Sprintf(`State "%s" is repeated.`, state.AsString())})
+				continue
+			}
+			te := &TransitionError{PrintOrderID: s.PrintOrderId, From: current, To: state}
+			tl.anomalies = append(tl.anomalies, Anomaly{Kind: ANOMALY_OUT_OF_ORDER, Status: s, Message: te.Error()})
+		}
+
+		tl.changes = append(tl.changes, StateChange{From: current, To: state, At: t, Status: s})
+		current = state
+	}
+
+	return tl
+}
+
+// Returns the current state. Returns STATE_NONE if there are no statuses with known states.
+func (tl *Timeline) Current() State {
+	if len(tl.changes) == 0 {
+		return STATE_NONE
+	}
+	return tl.changes[len(tl.changes)-1].To
+}
+
+// Returns the last status, whether its state is known or not. Returns nil if there are no statuses.
+func (tl *Timeline) Last() *Status {
+	if len(tl.statuses) == 0 {
+		return nil
+	}
+	return tl.statuses[len(tl.statuses)-1]
+}
+
+// Returns the state at time t. Returns STATE_NONE for times before the first status.
+func (tl *Timeline) At(t time.Time) State {
+	state := STATE_NONE
+	for _, v := range tl.changes {
+		if v.At.After(t) {
+			break
+		}
+		state = v.To
+	}
+	return state
+}
+
+// Returns the total time spent in state. Time in the current state is counted until now, unless it is terminal.
+func (tl *Timeline) DurationIn(state State) time.Duration {
+	var d time.Duration
+	for k, v := range tl.changes {
+		if v.To != state {
+			continue
+		}
+
+		switch {
+		case k+1 < len(tl.changes):
+			d += tl.changes[k+1].At.Sub(v.At)
+		case !state.IsTerminal():
+			d += tl.now().Sub(v.At)
+		}
+	}
+	return d
+}
+
+// Returns the changes of state in order. Repeated states are left out, except Partially sent which may legally repeat.
+func (tl *Timeline) Transitions() []StateChange {
+	return append([]StateChange(nil), tl.changes...)
+}
+
+// Returns the statuses that do not fit in the timeline.
+func (tl *Timeline) Anomalies() []Anomaly {
+	return append([]Anomaly(nil), tl.anomalies...)
+}
+
+// Returns the statuses in order.
+func (tl *Timeline) Statuses() StatusList {
+	return append(StatusList(nil), tl.statuses...)
+}
@@ -0,0 +1,92 @@
+package printorderstatus
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimelineOrdersStatusesWithoutMutating(t *testing.T) {
+	t.Parallel()
+	l := StatusList{
+		{ID: 3, Status: STATE_IN_PRODUCTION.AsString(), CreatedAt: "2017-01-02 00:00:00"},
+		{ID: 2, Status: STATE_ACCEPTED.AsString(), CreatedAt: "2017-01-01 12:00:00"},
+		{ID: 1, Status: STATE_EXPORTED.AsString(), CreatedAt: "2017-01-01 12:00:00"},
+		{ID: 4, Status: STATE_SENT.AsString(), UpdatedAt: "2017-01-04 00:00:00"},
+	}
+
+	tl := NewTimeline(l)
+
+	if l[0].ID != 3 {
+		t.Error("Expected status list to be left unchanged.")
+	}
+	if tl.Current() != STATE_SENT || tl.Last().ID != 4 {
+		t.Errorf("Expected current state to be sent but got %d.", tl.Current())
+	}
+	if len(tl.Anomalies()) != 0 {
+		t.Errorf("Expected no anomalies but got: %v", tl.Anomalies())
+	}
+
+	changes := tl.Transitions()
+	expected := []State{STATE_EXPORTED, STATE_ACCEPTED, STATE_IN_PRODUCTION, STATE_SENT}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d transitions but got %d.", len(expected), len(changes))
+	}
+	for k, v := range changes {
+		if v.To != expected[k] || (k > 0 && v.From != expected[k-1]) {
+			t.Errorf(`Transition %d did not match expected: %+v`, k, v)
+		}
+	}
+
+	at := func(s string) time.Time {
+		v, _ := time.Parse("2006-01-02 15:04:05", s)
+		return v
+	}
+	if s := tl.At(at("2017-01-03 00:00:00")); s != STATE_IN_PRODUCTION {
+		t.Errorf("Expected in production state at time but got %d.", s)
+	}
+	if s := tl.At(at("2016-01-01 00:00:00")); s != STATE_NONE {
+		t.Errorf("Expected no state before first status but got %d.", s)
+	}
+	if d := tl.DurationIn(STATE_IN_PRODUCTION); d != 48*time.Hour {
+		t.Errorf("Expected 48 hours in production but got %s.", d)
+	}
+	if d := tl.DurationIn(STATE_SENT); d <= 0 {
+		t.Errorf("Expected time in current state to be counted until now but got %s.", d)
+	}
+}
+
+func TestTimelineReportsAnomalies(t *testing.T) {
+	t.Parallel()
+	tl := NewTimeline(StatusList{
+		{ID: 1, Status: STATE_ACCEPTED.AsString(), CreatedAt: "2017-01-01 00:00:00"},
+		{ID: 2, Status: STATE_ACCEPTED.AsString(), CreatedAt: "2017-01-02 00:00:00"},
+		{ID: 3, Status: STATE_DELIVERED.AsString(), CreatedAt: "2017-01-03 00:00:00"},
+		{ID: 4, Status: "Lost", CreatedAt: "2017-01-04 00:00:00"},
+		{ID: 5, Status: STATE_SENT.AsString(), CreatedAt: "invalid"},
+	})
+
+	kinds := map[int]string{}
+	for _, v := range tl.Anomalies() {
+		kinds[v.Status.ID] = v.Kind
+	}
+
+	expected := map[int]string{2: ANOMALY_DUPLICATE, 3: ANOMALY_OUT_OF_ORDER, 4: ANOMALY_UNKNOWN_STATE, 5: ANOMALY_INVALID_TIME}
+	for id, kind := range expected {
+		if kinds[id] != kind {
+			t.Errorf(`Expected status %d to be reported as "%s" but got "%s".`, id, kind, kinds[id])
+		}
+	}
+
+	if tl.Current() != STATE_DELIVERED || len(tl.Transitions()) != 2 {
+		t.Errorf("Expected out of order state to be current but got %d.", tl.Current())
+	}
+}
+
+func TestTimelineOfNoStatuses(t *testing.T) {
+	t.Parallel()
+	tl := NewTimeline(nil)
+
+	if tl.Current() != STATE_NONE || tl.Last() != nil || len(tl.Transitions()) != 0 {
+		t.Error("Expected empty timeline.")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package printorderstatus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+)
+
+// State of print orders without statuses.
+const STATE_NONE State = 0
+
+// Legal transitions between states. Aborted print orders can not change state.
+var transitions map[State][]State = map[State][]State{
+	STATE_NONE:           {STATE_EXPORTED, STATE_ACCEPTED},
+	STATE_EXPORTED:       {STATE_ACCEPTED, STATE_ABORTED},
+	STATE_ACCEPTED:       {STATE_IN_PRODUCTION, STATE_ABORTED},
+	STATE_IN_PRODUCTION:  {STATE_SENT, STATE_PARTIALLY_SENT, STATE_ABORTED},
+	STATE_PARTIALLY_SENT: {STATE_PARTIALLY_SENT, STATE_SENT, STATE_DELIVERED, STATE_RETURNED},
+	STATE_SENT:           {STATE_DELIVERED, STATE_RETURNED},
+	STATE_DELIVERED:      {STATE_RETURNED},
+	STATE_RETURNED:       {STATE_RESEND, STATE_ABORTED},
+	STATE_RESEND:         {STATE_IN_PRODUCTION, STATE_SENT, STATE_ABORTED},
+}
+
+// Checks if a print order in state from may change to state to.
+// Print orders move from Exported through Accepted and In production to Sent, or Partially sent for orders sent in
+// several parcels, and then to Delivered. Sent and delivered orders may be Returned and then Resend. Orders may be
+// Aborted until they are sent, and again once Returned or Resend. Apart from Partially sent, a state can not follow
+// itself.
+func CanTransition(from, to State) bool {
+	for _, v := range transitions[from] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Sentinel error for classifying a TransitionError with errors.Is.
+var ErrIllegalTransition = errors.New("Illegal transition")
+
+// TransitionError is returned for illegal transitions of print orders.
+type TransitionError struct {
+	PrintOrderID int
+	From         State
+	To           State
+}
+
+// Error method to fulfil the error interface.
+func (e *TransitionError) Error() string {
+	from := e.From.AsString()
+	if from == "" {
+		from = "no status"
+	}
+	return fmt.Sprintf(`Print order %d can not change from "%s" to "%s".`, e.PrintOrderID, from, e.To.AsString())
+}
+
+// Is method for matching ErrIllegalTransition with errors.Is.
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrIllegalTransition
+}
+
+// ProductionAPIGetPoster defines how the client should perform GET and POST calls.
+type ProductionAPIGetPoster interface {
+	ProductionAPIGetter
+	ProductionAPIPoster
+}
+
+// ProductionAPIContextGetPoster defines how the client should perform context aware GET and POST calls.
+type ProductionAPIContextGetPoster interface {
+	ProductionAPIContextGetter
+	ProductionAPIContextPoster
+}
+
+// Client performing context aware GET and POST calls.
+type getPosterWithContext struct {
+	production.ContextGetter
+	production.ContextPoster
+}
+
+// Changes the state of print order to state to, posting a status with message.
+// The current state is read from the statuses of the print order, and a *TransitionError is returned without
+// posting anything if the change is not allowed by CanTransition. To post the status anyway, use New and Store.
+// Returns the posted status.
+func Transition(c ProductionAPIGetPoster, printOrderID int, to State, message string) (*Status, error) {
+	return TransitionContext(context.Background(), getPosterWithContext{production.GetterWithContext(c), production.PosterWithContext(c)}, printOrderID, to, message)
+}
+
+// Changes the state of print order to state to, posting a status with message.
+// The current state is read from the statuses of the print order, and a *TransitionError is returned without
+// posting anything if the change is not allowed by CanTransition. To post the status anyway, use New and StoreContext.
+// The requests are aborted if ctx is cancelled or its deadline expires.
+// Returns the posted status.
+func TransitionContext(ctx context.Context, c ProductionAPIContextGetPoster, printOrderID int, to State, message string) (*Status, error) {
+	if to.AsString() == "" {
+		return nil, fmt.Errorf(`Unknown state: "%d".`, to)
+	}
+
+	from, err := CurrentStateContext(ctx, c, printOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(from, to) {
+		return nil, &TransitionError{PrintOrderID: printOrderID, From: from, To: to}
+	}
+
+	s := New(to, printOrderID, message)
+	if err := s.StoreContext(ctx, c); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Returns the current state of print order, read from its statuses. Returns STATE_NONE if it has none.
+func CurrentState(c ProductionAPIGetter, printOrderID int) (State, error) {
+	return CurrentStateContext(context.Background(), production.GetterWithContext(c), printOrderID)
+}
+
+// Returns the current state of print order, read from its statuses. Returns STATE_NONE if it has none.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func CurrentStateContext(ctx context.Context, c ProductionAPIContextGetter, printOrderID int) (State, error) {
+	last, err := lastStatusContext(ctx, c, printOrderID)
+	if err != nil || last == nil {
+		return STATE_NONE, err
+	}
+	return ParseState(last.Status)
+}
+
+// Returns the last status of print order, or nil if it has none.
+func lastStatusContext(ctx context.Context, c ProductionAPIContextGetter, printOrderID int) (*Status, error) {
+	q, err := Query().PrintOrderID(printOrderID).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := IndexAllContext(ctx, c, 0, q...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTimeline(l).Last(), nil
+}
@@ -0,0 +1,99 @@
+package printorderstatus
+
+import (
+	"errors"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		from     State
+		to       State
+		expected bool
+	}{
+		{STATE_NONE, STATE_EXPORTED, true},
+		{STATE_EXPORTED, STATE_ACCEPTED, true},
+		{STATE_ACCEPTED, STATE_IN_PRODUCTION, true},
+		{STATE_IN_PRODUCTION, STATE_PARTIALLY_SENT, true},
+		{STATE_PARTIALLY_SENT, STATE_PARTIALLY_SENT, true},
+		{STATE_SENT, STATE_DELIVERED, true},
+		{STATE_DELIVERED, STATE_RETURNED, true},
+		{STATE_RETURNED, STATE_RESEND, true},
+		{STATE_RETURNED, STATE_ABORTED, true},
+		{STATE_RESEND, STATE_ABORTED, true},
+		{STATE_PARTIALLY_SENT, STATE_ABORTED, false},
+		{STATE_EXPORTED, STATE_DELIVERED, false},
+		{STATE_ABORTED, STATE_ACCEPTED, false},
+		{STATE_SENT, STATE_ABORTED, false},
+		{STATE_SENT, STATE_SENT, false},
+	}
+
+	for _, v := range tests {
+		if got := CanTransition(v.from, v.to); got != v.expected {
+			t.Errorf(`Expected transition from "%s" to "%s" to be %t.`, v.from.AsString(), v.to.AsString(), v.expected)
+		}
+	}
+}
+
+func TestCanParseState(t *testing.T) {
+	t.Parallel()
+	for s := STATE_EXPORTED; s <= STATE_RESEND; s++ {
+		if got, err := ParseState(s.AsString()); err != nil || got != s {
+			t.Errorf(`Expected "%s" to parse to %d but got %d and error: %v`, s.AsString(), s, got, err)
+		}
+	}
+
+	if got, err := ParseState(" in PRODUCTION "); err != nil || got != STATE_IN_PRODUCTION {
+		t.Errorf("Expected state to be parsed regardless of case but got %d and error: %v", got, err)
+	}
+
+	if _, err := ParseState("Lost"); err == nil {
+		t.Error("Did not receive an error but was expecting one.")
+	}
+}
+
+func TestCanTransitionPrintOrder(t *testing.T) {
+	t.Parallel()
+	var posted *Status
+	c := &MockProductionAPIClient{
+		T: t,
+		GetCall: func(t *testing.T, endpoint production.Endpointer, model interface{}, queryParams ...func(q url.Values)) {
+			q := url.Values{}
+			for _, v := range queryParams {
+				v(q)
+			}
+			if len(q) == 0 {
+				t.Error("Expected statuses to be filtered on print order.")
+			}
+			model.(*IndexResponse).Data = StatusList{
+				{ID: 2, Status: STATE_ACCEPTED.AsString(), UpdatedAt: "2017-01-01 00:00:00"},
+				{ID: 1, Status: STATE_EXPORTED.AsString(), UpdatedAt: "2017-01-01 00:00:00"},
+			}
+		},
+		PostCall: func(t *testing.T, endpoint production.Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) {
+			posted = payload.(*Status)
+		},
+	}
+
+	s, err := Transition(c, 1, STATE_IN_PRODUCTION, "Printing")
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+	if posted != s || s.Status != STATE_IN_PRODUCTION.AsString() || s.PrintOrderId != 1 {
+		t.Errorf("Expected posted status but got: %+v", s)
+	}
+
+	posted = nil
+	_, err = Transition(c, 1, STATE_DELIVERED, "")
+	te := &TransitionError{}
+	if !errors.Is(err, ErrIllegalTransition) || !errors.As(err, &te) || te.From != STATE_ACCEPTED || te.To != STATE_DELIVERED {
+		t.Errorf("Expected illegal transition error but got: %v", err)
+	}
+	if posted != nil {
+		t.Error("Expected no status to be posted for an illegal transition.")
+	}
+}
@@ -7,12 +7,12 @@ package production
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"github.com/publitsweden/APIUtilityGoSDK/common"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 const (
@@ -37,23 +37,43 @@ type APICaller interface {
 type APIClient struct {
 	Client  APICaller
 	BaseUrl string
+	// Retry policy for failed calls. Calls are not retried if nil.
+	Retry *RetryPolicy
+	// Disables refreshing the API token and replaying the request on unauthorized responses.
+	DisableTokenRefresh bool
+	// Coordinates token refreshes between concurrent calls. Calls through the same APICaller are coordinated if nil.
+	TokenRefresher *TokenRefresher
+	// Limits the rate and concurrency of calls. Calls are not limited if nil.
+	Limiter Limiter
+	// Middleware applied to every call, in order. See Middleware.
+	Middleware []Middleware
+	// Logging of calls. Calls are not logged if nil.
+	Logging *Logging
+	// Tracer starting a span for each call. Calls are not traced if nil.
+	Tracer Tracer
+	// Metrics receiving measurements of calls. Calls are not measured if nil.
+	Metrics Metrics
+	// Circuit breaker failing calls fast while the API is degraded. Calls are always made if nil.
+	Breaker *CircuitBreaker
+	// Cache of GET responses. Responses are not cached if nil.
+	Cache *Cache
 }
 
 // StatusCheck checks if the Publit service is up.
 func (c *APIClient) StatusCheck() bool {
-	url := c.compileStatusCheckURL()
-
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	return c.StatusCheckContext(context.Background())
+}
 
+// StatusCheckContext checks if the Publit service is up.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (c *APIClient) StatusCheckContext(ctx context.Context) bool {
+	r, err := c.statusCheck(ctx)
 	if err != nil {
 		return false
 	}
 
-	// Use CallRaw since no authentication is needed for status check.
-	r, err := c.Client.CallRaw(req)
-
-	if err != nil {
-		return false
+	if r.Body != nil {
+		defer r.Body.Close()
 	}
 
 	if r.StatusCode != http.StatusOK {
@@ -63,6 +83,22 @@ func (c *APIClient) StatusCheck() bool {
 	return true
 }
 
+// Calls the status check.
+func (c *APIClient) statusCheck(ctx context.Context) (*http.Response, error) {
+	url := c.compileStatusCheckURL()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Use CallRaw since no authentication is needed for status check.
+	return c.observe(RESOURCE_STATUSCHECK, req, func(req *http.Request) (*http.Response, error) {
+		return c.send(req, c.Client.CallRaw)
+	})
+}
+
 // Compiles statuscheck URL against the production API.
 func (c APIClient) compileStatusCheckURL() string {
 	return fmt.Sprintf("%s/%s/%s", c.BaseUrl, API_VERSION, RESOURCE_STATUSCHECK)
@@ -70,8 +106,19 @@ func (c APIClient) compileStatusCheckURL() string {
 
 // Performs a GET method action against the Publit production API.
 func (c APIClient) Get(endpoint Endpointer, model interface{}, queryParams ...func(q url.Values)) error {
-	endUrl := c.CompileEndpointURL(endpoint.GetEndpoint())
-	req, _ := http.NewRequest(http.MethodGet, endUrl, nil)
+	return c.GetContext(context.Background(), endpoint, model, queryParams...)
+}
+
+// Performs a GET method action against the Publit production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (c APIClient) GetContext(ctx context.Context, endpoint Endpointer, model interface{}, queryParams ...func(q url.Values)) error {
+	e := endpoint.GetEndpoint()
+	endUrl := c.CompileEndpointURL(e)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endUrl, nil)
+
+	if err != nil {
+		return err
+	}
 
 	q := req.URL.Query()
 	for _, v := range queryParams {
@@ -79,13 +126,19 @@ func (c APIClient) Get(endpoint Endpointer, model interface{}, queryParams ...fu
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.Client.Call(req)
-	defer resp.Body.Close()
+	if c.Cache != nil && c.Cache.ttl(e) != CACHE_NO_STORE {
+		return c.cachedGet(e, req, model)
+	}
 
+	resp, err := c.call(e, req)
 	if err != nil {
 		return err
 	}
 
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return MakeResponseError(resp)
 	}
@@ -101,17 +154,30 @@ func (c APIClient) Get(endpoint Endpointer, model interface{}, queryParams ...fu
 
 // Performs a POST method action against the Publit production API.
 func (c APIClient) Post(endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
-	return c.postPut(http.MethodPost, endpoint, payload, result, headers...)
+	return c.PostContext(context.Background(), endpoint, payload, result, headers...)
+}
+
+// Performs a POST method action against the Publit production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (c APIClient) PostContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	return c.postPut(ctx, http.MethodPost, endpoint, payload, result, headers...)
 }
 
 // Performs a PUT method action against the Publit production API.
 func (c APIClient) Put(endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
-	return c.postPut(http.MethodPut, endpoint, payload, result, headers...)
+	return c.PutContext(context.Background(), endpoint, payload, result, headers...)
+}
+
+// Performs a PUT method action against the Publit production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (c APIClient) PutContext(ctx context.Context, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	return c.postPut(ctx, http.MethodPut, endpoint, payload, result, headers...)
 }
 
 // Performs a post or put method action against the Publit production API.
-func (c APIClient) postPut(method string, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
-	endUrl := c.CompileEndpointURL(endpoint.GetEndpoint())
+func (c APIClient) postPut(ctx context.Context, method string, endpoint Endpointer, payload interface{}, result interface{}, headers ...func(h *http.Header)) error {
+	e := endpoint.GetEndpoint()
+	endUrl := c.CompileEndpointURL(e)
 
 	body, err := json.Marshal(payload)
 
@@ -119,7 +185,12 @@ func (c APIClient) postPut(method string, endpoint Endpointer, payload interface
 		return err
 	}
 
-	req, _ := http.NewRequest(method, endUrl, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, endUrl, bytes.NewBuffer(body))
+
+	if err != nil {
+		return err
+	}
+
 	req.Header.Set("Content-Type", "application/json")
 
 	h := &req.Header
@@ -127,7 +198,7 @@ func (c APIClient) postPut(method string, endpoint Endpointer, payload interface
 		v(h)
 	}
 
-	resp, err := c.Client.Call(req)
+	resp, err := c.call(e, req)
 	if err != nil {
 		return err
 	}
@@ -151,19 +222,33 @@ func (c APIClient) postPut(method string, endpoint Endpointer, payload interface
 
 // Performs a DELETE http call against the Publit production API.
 func (c APIClient) Delete(endpoint Endpointer, result interface{}, headers ...func(h *http.Header)) error {
-	endUrl := c.CompileEndpointURL(endpoint.GetEndpoint())
-	req, _ := http.NewRequest(http.MethodDelete, endUrl, nil)
+	return c.DeleteContext(context.Background(), endpoint, result, headers...)
+}
+
+// Performs a DELETE http call against the Publit production API.
+// The request is aborted if ctx is cancelled or its deadline expires.
+func (c APIClient) DeleteContext(ctx context.Context, endpoint Endpointer, result interface{}, headers ...func(h *http.Header)) error {
+	e := endpoint.GetEndpoint()
+	endUrl := c.CompileEndpointURL(e)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endUrl, nil)
+
+	if err != nil {
+		return err
+	}
 
 	h := &req.Header
 	for _, v := range headers {
 		v(h)
 	}
 
-	resp, err := c.Client.Call(req)
+	resp, err := c.call(e, req)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		return MakeResponseError(resp)
@@ -178,26 +263,117 @@ func (c APIClient) Delete(endpoint Endpointer, result interface{}, headers ...fu
 	return nil
 }
 
-// Compiles regular endpoints URL.
-func (c APIClient) CompileEndpointURL(endpoint string) string {
-	return fmt.Sprintf("%v/%v/%v/%v", c.BaseUrl, API, API_VERSION, endpoint)
+// Information about a call, shared between the layers handling it through the request context.
+type callInfo struct {
+	endpoint string
+	attempts int
 }
 
-// Attempts to make a better response error from response.
-func MakeResponseError(resp *http.Response) error {
-	if resp.Header.Get("Content-Type") == "application/json" {
-		APIErr := &common.APIErrorResponse{}
-		err := json.NewDecoder(resp.Body).Decode(APIErr)
-		if err == nil && APIErr.HasInformation() { // Only return this error message if APIErr has information.
-			return APIErr.GetAsError()
+// Context key for callInfo.
+type callInfoKey struct{}
+
+// Performs call to endpoint through the APICaller, unless the circuit of the endpoint is open.
+func (c APIClient) call(endpoint string, req *http.Request) (*http.Response, error) {
+	if c.Breaker == nil {
+		return c.observe(endpoint, req, c.callWithTokenRefresh)
+	}
+
+	done, err := c.Breaker.allow(req.Context(), endpoint, c.StatusCheckContext)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.observe(endpoint, req, c.callWithTokenRefresh)
+	done(resp, err)
+	return resp, err
+}
+
+// Performs call to endpoint, tracing and logging it.
+func (c APIClient) observe(endpoint string, req *http.Request, call RoundTripFunc) (*http.Response, error) {
+	info := &callInfo{endpoint: endpoint}
+	ctx := context.WithValue(req.Context(), callInfoKey{}, info)
+	// The request ID is set once per call, so that retries and replays after token refresh share it.
+	if _, ok := RequestIDFromContext(ctx); !ok {
+		ctx = WithRequestID(ctx, newRequestID())
+	}
+	req = req.WithContext(ctx)
+	req, endSpan := c.startSpan(endpoint, req)
+
+	start := time.Now()
+	resp, err := call(req)
+	latency := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.CallMetrics().ObserveRequest(req.Method, EndpointTemplate(endpoint), status, latency)
+
+	endSpan(resp, err, info)
+	c.logCall(info, req, resp, err, latency)
+
+	return resp, err
+}
+
+// Performs call through the APICaller, retrying according to the retry policy.
+func (c APIClient) callWithRetry(req *http.Request) (*http.Response, error) {
+	attempts := c.Retry.attemptsFor(req)
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 {
+			var err error
+			r, err = rewindRequest(req)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.send(r, c.Client.Call)
+		if resp != nil && resp.Request == nil {
+			resp.Request = r
 		}
+
+		if attempt >= attempts || !c.Retry.shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := c.Retry.delay(attempt, resp)
+		discardResponse(resp)
+
+		if info, ok := req.Context().Value(callInfoKey{}).(*callInfo); ok {
+			c.CallMetrics().IncRetry(req.Method, EndpointTemplate(info.endpoint))
+		}
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Performs a single call through the limiter and middleware, if any.
+func (c APIClient) send(req *http.Request, call RoundTripFunc) (*http.Response, error) {
+	if info, ok := req.Context().Value(callInfoKey{}).(*callInfo); ok {
+		info.attempts++
+	}
+
+	rt := chain(call, c.Middleware)
+
+	if c.Limiter == nil {
+		return rt(req)
 	}
 
-	// Special check for unauthorized reponse.
-	if resp.StatusCode == http.StatusUnauthorized {
-		return errors.New(fmt.Sprintf(`Unauthorized. Code: "%v"`, resp.StatusCode))
+	release, err := c.Limiter.Acquire(req.Context())
+	if err != nil {
+		return nil, err
 	}
 
-	// Default
-	return errors.New(fmt.Sprintf(`Response not ok. No information given. Code: "%v"`, resp.StatusCode))
+	resp, err := rt(req)
+	release(resp, err)
+	return resp, err
+}
+
+// Compiles regular endpoints URL.
+func (c APIClient) CompileEndpointURL(endpoint string) string {
+	return fmt.Sprintf("%v/%v/%v/%v", c.BaseUrl, API, API_VERSION, endpoint)
 }
@@ -3,6 +3,7 @@ package production_test
 import (
 	. "github.com/publitsweden/ProductionAPIGoSDK"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,7 +31,7 @@ func TestCanCheckStatus(t *testing.T) {
 
 			baseurl := "somebaseurl"
 
-			c := &APIClient{caller, baseurl}
+			c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 			ok := c.StatusCheck()
 
@@ -49,7 +50,7 @@ func TestCanCheckStatus(t *testing.T) {
 
 			baseurl := "somebaseurl"
 
-			c := &APIClient{caller, baseurl}
+			c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 			ok := c.StatusCheck()
 
@@ -69,7 +70,7 @@ func TestCanCheckStatus(t *testing.T) {
 
 			baseurl := "somebaseurl"
 
-			c := &APIClient{caller, baseurl}
+			c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 			ok := c.StatusCheck()
 
@@ -89,7 +90,7 @@ func TestCanPerformGetRequest(t *testing.T) {
 
 	baseurl := "somebaseurl"
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	int := &struct {
 		Some string `json:"some"`
@@ -116,7 +117,7 @@ func TestGetReturnsErrorIfCallFails(t *testing.T) {
 
 	baseurl := "somebaseurl"
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	int := &struct{}{}
 	err := c.Get(NewEndpoint(), int)
@@ -134,7 +135,7 @@ func TestGetReturnsErrorIfStatusCodeNotOk(t *testing.T) {
 
 	baseurl := "somebaseurl"
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	int := &struct{}{}
 	err := c.Get(NewEndpoint(), int)
@@ -152,7 +153,7 @@ func TestGetReturnsErrorIfBodyCanNotBeUnmarshalled(t *testing.T) {
 
 	baseurl := "somebaseurl"
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	int := &struct{}{}
 	err := c.Get(NewEndpoint(), int)
@@ -181,7 +182,7 @@ func TestCanPerformPOSTRequest(t *testing.T) {
 
 		ic := i
 
-		json.Unmarshal(b, ic)
+		json.Unmarshal(b, &ic)
 
 		if ic.Name != i.Name {
 			t.Error("Request body did not match expected.")
@@ -191,7 +192,7 @@ func TestCanPerformPOSTRequest(t *testing.T) {
 	baseurl := "somebaseurl"
 	caller.Response = createCallerResponse(http.StatusOK, `{"name":"newTestName"}`)
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	err := c.Post(NewEndpoint(), &i, j)
 
@@ -223,7 +224,7 @@ func TestCanPerformPUTRequest(t *testing.T) {
 
 		ic := i
 
-		json.Unmarshal(b, ic)
+		json.Unmarshal(b, &ic)
 
 		if ic.Name != i.Name {
 			t.Error("Request body did not match expected.")
@@ -233,7 +234,7 @@ func TestCanPerformPUTRequest(t *testing.T) {
 	baseurl := "somebaseurl"
 	caller.Response = createCallerResponse(http.StatusOK, `{"name":"newTestName"}`)
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	err := c.Put(NewEndpoint(), &i, j)
 
@@ -258,7 +259,7 @@ func TestCanPerformDeleteRequest(t *testing.T) {
 	baseurl := "somebaseurl"
 	caller.Response = createCallerResponse(http.StatusOK, `{"name":"newTestName"}`)
 
-	c := &APIClient{caller, baseurl}
+	c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 	err := c.Delete(NewEndpoint(), &i)
 
@@ -271,6 +272,71 @@ func TestCanPerformDeleteRequest(t *testing.T) {
 	}
 }
 
+func TestRequestsCarryContext(t *testing.T) {
+	t.Parallel()
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	caller := &MockAPICaller{}
+	caller.T = t
+	caller.CallTestCallback = func(t *testing.T, r *http.Request) {
+		if r.Context().Value(ctxKey{}) != "value" {
+			t.Error("Request did not carry the provided context.")
+		}
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+	i := struct{}{}
+
+	caller.Response = createCallerResponse(http.StatusOK, `{}`)
+	if err := c.GetContext(ctx, NewEndpoint(), &i); err != nil {
+		t.Error("Received an error but was not expecting to.", err)
+	}
+
+	caller.Response = createCallerResponse(http.StatusOK, `{}`)
+	if err := c.PostContext(ctx, NewEndpoint(), &i, &i); err != nil {
+		t.Error("Received an error but was not expecting to.", err)
+	}
+
+	caller.Response = createCallerResponse(http.StatusOK, `{}`)
+	if err := c.PutContext(ctx, NewEndpoint(), &i, &i); err != nil {
+		t.Error("Received an error but was not expecting to.", err)
+	}
+
+	caller.Response = createCallerResponse(http.StatusOK, `{}`)
+	if err := c.DeleteContext(ctx, NewEndpoint(), &i); err != nil {
+		t.Error("Received an error but was not expecting to.", err)
+	}
+}
+
+func TestGetterWithContextChecksContext(t *testing.T) {
+	t.Parallel()
+	called := false
+	caller := &MockAPICaller{}
+	caller.T = t
+	caller.CallTestCallback = func(t *testing.T, r *http.Request) {
+		called = true
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `{}`)
+
+	// Hide the context aware methods of the APIClient behind the plain Getter interface.
+	g := struct{ Getter }{APIClient{Client: caller, BaseUrl: "somebaseurl"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	i := struct{}{}
+	err := GetterWithContext(g).GetContext(ctx, NewEndpoint(), &i)
+
+	if err != context.Canceled {
+		t.Errorf(`Expected context.Canceled but got: "%v"`, err)
+	}
+
+	if called {
+		t.Error("Call was made even though context was cancelled.")
+	}
+}
+
 func TestPostPutErrors(t *testing.T) {
 	t.Parallel()
 	table := []struct {
@@ -287,7 +353,7 @@ func TestPostPutErrors(t *testing.T) {
 
 				baseurl := "somebaseurl"
 
-				c := &APIClient{caller, baseurl}
+				c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 				// Run method through POST (would be just as fine with PUT
 				err := c.Post(NewEndpoint(), &i, &i)
@@ -304,7 +370,7 @@ func TestPostPutErrors(t *testing.T) {
 				caller.Response = createCallerResponse(http.StatusOK, "")
 
 				baseurl := "somebaseurl"
-				c := &APIClient{caller, baseurl}
+				c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 				i := struct{}{}
 				// Run method through POST (would be just as fine with PUT
@@ -322,7 +388,7 @@ func TestPostPutErrors(t *testing.T) {
 				caller.Response = createCallerResponse(http.StatusBadRequest, "")
 
 				baseurl := "somebaseurl"
-				c := &APIClient{caller, baseurl}
+				c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 				i := struct{ Name string }{}
 				err := c.Post(NewEndpoint(), &i, &i)
@@ -339,7 +405,7 @@ func TestPostPutErrors(t *testing.T) {
 				caller.Response = createCallerResponse(http.StatusOK, `{"somwire,ddgd:""jsonstructur,,,:"newTestName"}`)
 
 				baseurl := "somebaseurl"
-				c := &APIClient{caller, baseurl}
+				c := &APIClient{Client: caller, BaseUrl: baseurl}
 
 				i := struct {
 					Name string `json:"name"`
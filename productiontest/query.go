@@ -0,0 +1,294 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package productiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query parameter keys handled by the fake.
+const (
+	QUERY_KEY_WITH      = "with"
+	QUERY_KEY_LIMIT     = "limit"
+	QUERY_KEY_OFFSET    = "offset"
+	QUERY_KEY_ORDER_BY  = "order_by"
+	QUERY_KEY_ORDER_DIR = "order_dir"
+	QUERY_KEY_SCOPE     = "scope"
+)
+
+// Keys that are not attribute filters.
+var reservedKeys map[string]bool = map[string]bool{
+	common.QUERY_KEY_AUX: true,
+	QUERY_KEY_WITH:       true,
+	QUERY_KEY_LIMIT:      true,
+	QUERY_KEY_OFFSET:     true,
+	QUERY_KEY_ORDER_BY:   true,
+	QUERY_KEY_ORDER_DIR:  true,
+	QUERY_KEY_SCOPE:      true,
+}
+
+// Attribute filter value with an operator, e.g. "2017-01-01 00:00:00[>=]".
+var filterValue = regexp.MustCompile(`^(.*)\[(=|!=|<>|>|>=|<|<=|like)\]$`)
+
+// Parsed index query.
+type indexQuery struct {
+	filters  []filter
+	with     []string
+	orderBy  []string
+	orderDir string
+	limit    int
+	offset   int
+	hasLimit bool
+}
+
+// Attribute filter.
+type filter struct {
+	attribute string
+	operator  string
+	value     string
+}
+
+// Parses query params of a request.
+func parseQuery(q url.Values) (*indexQuery, error) {
+	iq := &indexQuery{orderDir: production.ORDER_DIR_ASC}
+
+	for k, values := range q {
+		if reservedKeys[k] {
+			continue
+		}
+		for _, v := range values {
+			f := filter{attribute: k, operator: string(production.OPERATOR_EQUAL), value: v}
+			if m := filterValue.FindStringSubmatch(v); m != nil {
+				f.value, f.operator = m[1], m[2]
+			}
+			iq.filters = append(iq.filters, f)
+		}
+	}
+
+	// Sort filters to make validation errors deterministic.
+	sort.SliceStable(iq.filters, func(i, j int) bool { return iq.filters[i].attribute < iq.filters[j].attribute })
+
+	if v := q.Get(QUERY_KEY_WITH); v != "" {
+		iq.with = strings.Split(v, ",")
+	}
+
+	if v := q.Get(QUERY_KEY_ORDER_BY); v != "" {
+		iq.orderBy = strings.Split(v, ",")
+	}
+
+	if v := q.Get(QUERY_KEY_ORDER_DIR); v != "" {
+		if v != production.ORDER_DIR_ASC && v != production.ORDER_DIR_DESC {
+			return nil, fmt.Errorf(`Invalid order direction: "%s".`, v)
+		}
+		iq.orderDir = v
+	}
+
+	if v := q.Get(QUERY_KEY_LIMIT); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf(`Invalid limit: "%s".`, v)
+		}
+		iq.limit = limit
+		iq.hasLimit = true
+	}
+
+	if v := q.Get(QUERY_KEY_OFFSET); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf(`Invalid offset: "%s".`, v)
+		}
+		iq.offset = offset
+	}
+
+	return iq, nil
+}
+
+// Returns the attributes of item, as encoded to JSON. Nested objects and lists are left out.
+func attributesOf(item interface{}) map[string]string {
+	b, _ := json.Marshal(item)
+	raw := map[string]interface{}{}
+	json.Unmarshal(b, &raw)
+
+	attrs := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			attrs[k] = v
+		case float64:
+			attrs[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			if v {
+				attrs[k] = "1"
+			} else {
+				attrs[k] = "0"
+			}
+		case nil:
+			attrs[k] = ""
+		}
+	}
+	return attrs
+}
+
+// Filters, orders and pages items according to the query. Returns the page and the total number of matching items.
+func applyQuery[T any](items []*T, iq *indexQuery, attributes []string) ([]*T, int, error) {
+	known := make(map[string]bool, len(attributes))
+	for _, v := range attributes {
+		known[v] = true
+	}
+
+	for _, f := range iq.filters {
+		if !known[f.attribute] {
+			return nil, 0, fmt.Errorf(`Unknown attribute: "%s".`, f.attribute)
+		}
+	}
+
+	for _, v := range iq.orderBy {
+		if !known[v] {
+			return nil, 0, fmt.Errorf(`Unknown attribute to order by: "%s".`, v)
+		}
+	}
+
+	type entry struct {
+		item  *T
+		attrs map[string]string
+	}
+
+	var matched []entry
+	for _, v := range items {
+		e := entry{item: v, attrs: attributesOf(v)}
+		if matches(e.attrs, iq.filters) {
+			matched = append(matched, e)
+		}
+	}
+
+	if len(iq.orderBy) > 0 {
+		sort.SliceStable(matched, func(i, j int) bool {
+			for _, attr := range iq.orderBy {
+				c := compare(matched[i].attrs[attr], matched[j].attrs[attr])
+				if c == 0 {
+					continue
+				}
+				if iq.orderDir == production.ORDER_DIR_DESC {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	count := len(matched)
+
+	start := iq.offset
+	if start > count {
+		start = count
+	}
+	end := count
+	if iq.hasLimit && start+iq.limit < end {
+		end = start + iq.limit
+	}
+
+	page := make([]*T, 0, end-start)
+	for _, v := range matched[start:end] {
+		page = append(page, v.item)
+	}
+
+	return page, count, nil
+}
+
+// Checks if attrs match all filters.
+func matches(attrs map[string]string, filters []filter) bool {
+	for _, f := range filters {
+		v := attrs[f.attribute]
+		c := compare(v, f.value)
+
+		var ok bool
+		switch f.operator {
+		case "=":
+			ok = c == 0
+		case "!=", "<>":
+			ok = c != 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		case "like":
+			ok = like(v, f.value)
+		}
+
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Compares a and b numerically if both are numbers, otherwise as strings.
+// Publit timestamps compare correctly as strings.
+func compare(a string, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// Matches v against an SQL like pattern, where "%" matches any sequence of characters. Case insensitive.
+func like(v string, pattern string) bool {
+	parts := strings.Split(pattern, "%")
+	for k := range parts {
+		parts[k] = regexp.QuoteMeta(parts[k])
+	}
+	re, err := regexp.Compile("(?is)^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(v)
+}
+
+// Returns Next and Prev links for a page of an index.
+func pageLinks(u url.URL, iq *indexQuery, count int) (next string, prev string) {
+	if !iq.hasLimit {
+		return "", ""
+	}
+
+	q := u.Query()
+	q.Set(QUERY_KEY_LIMIT, strconv.Itoa(iq.limit))
+
+	if iq.offset+iq.limit < count {
+		q.Set(QUERY_KEY_OFFSET, strconv.Itoa(iq.offset+iq.limit))
+		u.RawQuery = q.Encode()
+		next = u.String()
+	}
+
+	if iq.offset > 0 {
+		offset := iq.offset - iq.limit
+		if offset < 0 {
+			offset = 0
+		}
+		q.Set(QUERY_KEY_OFFSET, strconv.Itoa(offset))
+		u.RawQuery = q.Encode()
+		prev = u.String()
+	}
+
+	return next, prev
+}
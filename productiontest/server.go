@@ -0,0 +1,727 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+// Provides an in-memory fake of the Publit production API for integration testing.
+//
+// The fake stores print orders, print data, files, statuses, delivery numbers and countries, serves presigned
+// URLs for file downloads and supports the with, attribute filter, limit/offset and order-by query params.
+// Failed calls get Publit style error payloads.
+//
+//	s := productiontest.NewServer()
+//	defer s.Close()
+//
+//	s.AddPrintOrders(&printorder.PrintOrder{ID: 1, ClientRef: "ref"})
+//
+//	c := s.Client()
+//	printorderstatus.New(printorderstatus.STATE_ACCEPTED, 1, "").Store(c)
+//
+//	posted := s.PostedStatuses()
+package productiontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"github.com/publitsweden/ProductionAPIGoSDK/deliverynumber"
+	"github.com/publitsweden/ProductionAPIGoSDK/file"
+	"github.com/publitsweden/ProductionAPIGoSDK/printdata"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorder"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Resource paths served by the fake.
+const (
+	PATH_PRINT_ORDERS     = "print_orders"
+	PATH_PRINT_DATA       = "print_order_print_data"
+	PATH_FILES            = "files"
+	PATH_STATUSES         = "print_order_statuses"
+	PATH_DELIVERY_NUMBERS = "print_order_delivery_numbers"
+	PATH_COUNTRIES        = "countries"
+)
+
+// Path prefix of presigned file URLs.
+const PRESIGNED_PATH = "/presigned/files/"
+
+// ErrorResponse is the error payload returned by the fake, encoded as Publit API error responses and decoded by
+// production.MakeResponseError into a common.APIErrorResponse.
+type ErrorResponse struct {
+	Code         int         `json:"Code"`
+	Type         string      `json:"Type"`
+	Errors       []ErrorInfo `json:"Errors"`
+	CombinedInfo string      `json:"CombinedInfo"`
+}
+
+// ErrorInfo is a single error of an ErrorResponse.
+type ErrorInfo struct {
+	Info string `json:"Info"`
+	Type string `json:"Type"`
+}
+
+// Server is an httptest.Server backed fake of the Publit production API.
+// All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu                    sync.Mutex
+	lastIDs               map[string]int
+	printOrders           []*printorder.PrintOrder
+	printData             []*printdata.PrintData
+	files                 []*file.File
+	contents              map[int][]byte
+	statuses              []*printorderstatus.Status
+	deliveryNumbers       []*deliverynumber.DeliveryNumber
+	countries             []*country.Country
+	postedStatuses        printorderstatus.StatusList
+	postedDeliveryNumbers deliverynumber.DeliveryNumberList
+	failures              []int
+}
+
+// Creates and starts new Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		lastIDs:  map[string]int{},
+		contents: map[int][]byte{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Returns an APIClient making calls against the fake.
+func (s *Server) Client() production.APIClient {
+	return production.APIClient{
+		Client:  &caller{client: s.Server.Client()},
+		BaseUrl: s.URL,
+	}
+}
+
+// APICaller performing plain calls against the fake.
+type caller struct {
+	client *http.Client
+}
+
+func (c *caller) Call(r *http.Request) (*http.Response, error) {
+	return c.client.Do(r)
+}
+
+func (c *caller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.client.Do(r)
+}
+
+func (c *caller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
+
+// Makes the next calls to the API, except status checks and downloads, fail with the given status codes, in order.
+func (s *Server) FailNext(statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statusCodes...)
+}
+
+// Adds print orders. Statuses, print data and delivery countries embedded in the print orders are added as well.
+// Items without ID are given one.
+func (s *Server) AddPrintOrders(printOrders ...*printorder.PrintOrder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range printOrders {
+		po := *v
+		po.ID = s.assignID(PATH_PRINT_ORDERS, po.ID)
+
+		for _, st := range po.Statuses {
+			st := *st
+			st.ID = s.assignID(PATH_STATUSES, st.ID)
+			st.PrintOrderId = po.ID
+			s.statuses = append(s.statuses, &st)
+		}
+
+		for _, pd := range po.PrintData {
+			s.addPrintData(pd, po.ID)
+		}
+
+		if po.DeliveryCountry != nil {
+			c := *po.DeliveryCountry
+			if c.ID == 0 || s.country(c.ID) == nil {
+				c.ID = s.assignID(PATH_COUNTRIES, c.ID)
+				s.countries = append(s.countries, &c)
+			}
+			po.DeliveryCountryId = strconv.Itoa(c.ID)
+		}
+
+		po.Statuses = nil
+		po.PrintData = nil
+		po.DeliveryCountry = nil
+		s.printOrders = append(s.printOrders, &po)
+	}
+}
+
+// Adds print data. Files embedded in the print data are added as well, without content.
+// Items without ID are given one.
+func (s *Server) AddPrintData(printData ...*printdata.PrintData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range printData {
+		s.addPrintData(v, v.PrintOrderID)
+	}
+}
+
+// Adds print data belonging to print order printOrderID. Must be called with mu held.
+func (s *Server) addPrintData(v *printdata.PrintData, printOrderID int) {
+	pd := *v
+	pd.ID = s.assignID(PATH_PRINT_DATA, pd.ID)
+	pd.PrintOrderID = printOrderID
+
+	if pd.File != nil {
+		f := *pd.File
+		f.ID = s.assignID(PATH_FILES, f.ID)
+		f.Presigned = ""
+		s.files = append(s.files, &f)
+		pd.FileID = f.ID
+	}
+
+	pd.File = nil
+	s.printData = append(s.printData, &pd)
+}
+
+// Adds file with content, which is served through its presigned URL. Returns the ID of the file.
+func (s *Server) AddFile(f *file.File, content []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := *f
+	c.ID = s.assignID(PATH_FILES, c.ID)
+	c.Presigned = ""
+	if c.Size == 0 {
+		c.Size = len(content)
+	}
+	s.files = append(s.files, &c)
+	s.contents[c.ID] = content
+	return c.ID
+}
+
+// Adds statuses. Items without ID are given one.
+func (s *Server) AddStatuses(statuses ...*printorderstatus.Status) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range statuses {
+		st := *v
+		st.ID = s.assignID(PATH_STATUSES, st.ID)
+		s.statuses = append(s.statuses, &st)
+	}
+}
+
+// Adds delivery numbers. Items without ID are given one.
+func (s *Server) AddDeliveryNumbers(deliveryNumbers ...*deliverynumber.DeliveryNumber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range deliveryNumbers {
+		d := *v
+		d.ID = s.assignID(PATH_DELIVERY_NUMBERS, d.ID)
+		s.deliveryNumbers = append(s.deliveryNumbers, &d)
+	}
+}
+
+// Adds countries. Items without ID are given one.
+func (s *Server) AddCountries(countries ...*country.Country) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range countries {
+		c := *v
+		c.ID = s.assignID(PATH_COUNTRIES, c.ID)
+		s.countries = append(s.countries, &c)
+	}
+}
+
+// Returns statuses posted through the API, in order.
+func (s *Server) PostedStatuses() printorderstatus.StatusList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyList(s.postedStatuses)
+}
+
+// Returns delivery numbers posted through the API, in order.
+func (s *Server) PostedDeliveryNumbers() deliverynumber.DeliveryNumberList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyList(s.postedDeliveryNumbers)
+}
+
+// Returns all statuses of print order printOrderID, seeded or posted.
+func (s *Server) Statuses(printOrderID int) printorderstatus.StatusList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyList(s.statusesOf(printOrderID))
+}
+
+// Returns the current delivery numbers of print order printOrderID, after any updates and deletes.
+func (s *Server) DeliveryNumbers(printOrderID int) deliverynumber.DeliveryNumberList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var l deliverynumber.DeliveryNumberList
+	for _, v := range s.deliveryNumbers {
+		if v.PrintOrderID == printOrderID {
+			l = append(l, v)
+		}
+	}
+	return copyList(l)
+}
+
+// Returns copies of the items of l.
+func copyList[L ~[]*T, T any](l L) L {
+	c := make(L, len(l))
+	for k, v := range l {
+		item := *v
+		c[k] = &item
+	}
+	return c
+}
+
+// Returns id, or the next free ID of resource if id is zero. Must be called with mu held.
+func (s *Server) assignID(resource string, id int) int {
+	if id == 0 {
+		id = s.lastIDs[resource] + 1
+	}
+	if id > s.lastIDs[resource] {
+		s.lastIDs[resource] = id
+	}
+	return id
+}
+
+// Handles requests.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apiPrefix := "/" + production.API + "/" + production.API_VERSION + "/"
+
+	switch {
+	case r.URL.Path == "/"+production.API_VERSION+"/"+production.RESOURCE_STATUSCHECK:
+		w.WriteHeader(http.StatusOK)
+	case strings.HasPrefix(r.URL.Path, PRESIGNED_PATH):
+		s.serveDownload(w, r)
+	case strings.HasPrefix(r.URL.Path, apiPrefix):
+		if len(s.failures) > 0 {
+			status := s.failures[0]
+			s.failures = s.failures[1:]
+			writeError(w, status, "Injected failure.")
+			return
+		}
+		s.serveAPI(w, r, strings.TrimPrefix(r.URL.Path, apiPrefix))
+	default:
+		writeError(w, http.StatusNotFound, "Not found.")
+	}
+}
+
+// Handles API requests for path, relative to the API version.
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, path string) {
+	resource, rawID, hasID := strings.Cut(path, "/")
+
+	id := 0
+	if hasID {
+		var err error
+		if id, err = strconv.Atoi(rawID); err != nil || id < 1 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf(`Invalid id: "%s".`, rawID))
+			return
+		}
+	}
+
+	switch {
+	case resource == PATH_PRINT_ORDERS && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.printOrders, func(v *printorder.PrintOrder) int { return v.ID }, printOrderRelations, s.expandPrintOrder)
+	case resource == PATH_PRINT_DATA && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.printData, func(v *printdata.PrintData) int { return v.ID }, printDataRelations, s.expandPrintData)
+	case resource == PATH_FILES && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.files, func(v *file.File) int { return v.ID }, nil, s.expandFile)
+	case resource == PATH_STATUSES && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.statuses, func(v *printorderstatus.Status) int { return v.ID }, nil, plain[printorderstatus.Status])
+	case resource == PATH_STATUSES && r.Method == http.MethodPost && !hasID:
+		s.postStatus(w, r)
+	case resource == PATH_DELIVERY_NUMBERS && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.deliveryNumbers, func(v *deliverynumber.DeliveryNumber) int { return v.ID }, nil, plain[deliverynumber.DeliveryNumber])
+	case resource == PATH_DELIVERY_NUMBERS && r.Method == http.MethodPost && !hasID:
+		s.postDeliveryNumber(w, r)
+	case resource == PATH_DELIVERY_NUMBERS && r.Method == http.MethodPut && hasID:
+		s.putDeliveryNumber(w, r, id)
+	case resource == PATH_DELIVERY_NUMBERS && r.Method == http.MethodDelete && hasID:
+		s.deleteDeliveryNumber(w, id)
+	case resource == PATH_COUNTRIES && r.Method == http.MethodGet:
+		serveGet(w, r, id, s.countries, func(v *country.Country) int { return v.ID }, nil, plain[country.Country])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf(`No route for %s "%s".`, r.Method, path))
+	}
+}
+
+// Returns a copy of item with the given relations loaded.
+type expander[T any] func(item *T, with []string, r *http.Request) *T
+
+// Handles GET requests for a resource, showing the item with id or indexing all items if id is zero.
+// Relations are the relations that can be loaded with the with query param.
+func serveGet[T any](w http.ResponseWriter, r *http.Request, id int, items []*T, idOf func(*T) int, relations map[string]bool, expand expander[T]) {
+	iq, err := parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for _, v := range iq.with {
+		if !relations[v] {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf(`Unknown relation: "%s".`, v))
+			return
+		}
+	}
+
+	if id != 0 {
+		for _, v := range items {
+			if idOf(v) != id {
+				continue
+			}
+			writeJSON(w, http.StatusOK, expand(v, iq.with, r))
+			return
+		}
+		writeError(w, http.StatusNotFound, fmt.Sprintf(`No item with id: "%d".`, id))
+		return
+	}
+
+	page, count, err := applyQuery(items, iq, attributeNames(new(T)))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := production.IndexResponse[[]*T]{Count: count, Data: make([]*T, 0, len(page))}
+	for _, v := range page {
+		resp.Data = append(resp.Data, expand(v, iq.with, r))
+	}
+
+	u := *r.URL
+	u.Scheme = "http"
+	u.Host = r.Host
+	resp.Next, resp.Prev = pageLinks(u, iq, count)
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Expander for resources without relations.
+func plain[T any](item *T, with []string, r *http.Request) *T {
+	c := *item
+	return &c
+}
+
+// Relations of print orders.
+var printOrderRelations map[string]bool = map[string]bool{
+	printorder.WITH_STATUSES:                      true,
+	printorder.WITH_PRINT_DATA:                    true,
+	printorder.WITH_PRINT_DATA_FILE:               true,
+	printorder.WITH_PRINT_DATA_MANIFESTATION:      true,
+	printorder.WITH_PRINT_DATA_MANIFESTATION_ISBN: true,
+	printorder.WITH_PRINT_DATA_PRINT_ITEM_PAPER:   true,
+	printorder.WITH_PRINT_DATA_PRINT_ITEM:         true,
+	printorder.WITH_PRINT_DATA_BOOK_BINDING:       true,
+	printorder.WITH_DELIVERY_COUNTRY:              true,
+}
+
+// Loads print order relations. Relations of print data not stored by the fake are accepted but left empty.
+func (s *Server) expandPrintOrder(item *printorder.PrintOrder, with []string, r *http.Request) *printorder.PrintOrder {
+	po := *item
+
+	var printDataWith []string
+	for _, v := range with {
+		switch {
+		case v == printorder.WITH_STATUSES:
+			po.Statuses = copyList(s.statusesOf(po.ID))
+		case v == printorder.WITH_DELIVERY_COUNTRY:
+			if id, err := strconv.Atoi(po.DeliveryCountryId); err == nil {
+				if c := s.country(id); c != nil {
+					cc := *c
+					po.DeliveryCountry = &cc
+				}
+			}
+		case strings.HasPrefix(v, printorder.WITH_PRINT_DATA+"."):
+			printDataWith = append(printDataWith, strings.TrimPrefix(v, printorder.WITH_PRINT_DATA+"."))
+			fallthrough
+		case v == printorder.WITH_PRINT_DATA:
+			po.PrintData = printdata.PrintDataList{}
+		}
+	}
+
+	if po.PrintData != nil {
+		for _, v := range s.printData {
+			if v.PrintOrderID != po.ID {
+				continue
+			}
+			po.PrintData = append(po.PrintData, s.expandPrintData(v, printDataWith, r))
+		}
+	}
+
+	return &po
+}
+
+// Relations of print data.
+var printDataRelations map[string]bool = map[string]bool{
+	printdata.WITH_MANIFESTATION:      true,
+	printdata.WITH_MANIFESTATION_ISBN: true,
+	printdata.WITH_FILE:               true,
+	printdata.WITH_PRINT_ITEM_PAPER:   true,
+	printdata.WITH_PRINT_ITEM:         true,
+	printdata.WITH_BOOK_BINDING:       true,
+}
+
+// Loads print data relations. Only files are stored by the fake, other relations are accepted but left empty.
+func (s *Server) expandPrintData(item *printdata.PrintData, with []string, r *http.Request) *printdata.PrintData {
+	pd := *item
+
+	for _, v := range with {
+		if v != printdata.WITH_FILE {
+			continue
+		}
+		for _, f := range s.files {
+			if f.ID == pd.FileID {
+				pd.File = s.expandFile(f, nil, r)
+			}
+		}
+	}
+
+	return &pd
+}
+
+// Sets the presigned URL of files if requested.
+func (s *Server) expandFile(item *file.File, with []string, r *http.Request) *file.File {
+	f := plain(item, with, r)
+
+	if r.URL.Query().Get(common.QUERY_KEY_AUX) == file.AUX_PRESIGNED {
+		f.Presigned = fmt.Sprintf("http://%s%s%d?X-Amz-Expires=900&X-Amz-Signature=%s", r.Host, PRESIGNED_PATH, f.ID, signature(f.ID))
+	}
+
+	return f
+}
+
+// Returns fake signature of presigned URL for file id.
+func signature(id int) string {
+	h := sha256.Sum256([]byte("productiontest/" + strconv.Itoa(id)))
+	return hex.EncodeToString(h[:8])
+}
+
+// Serves file content through presigned URLs.
+func (s *Server) serveDownload(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, PRESIGNED_PATH))
+	if err != nil || r.URL.Query().Get("X-Amz-Signature") != signature(id) {
+		http.Error(w, "Signature does not match.", http.StatusForbidden)
+		return
+	}
+
+	content, ok := s.contents[id]
+	if !ok {
+		http.Error(w, "No such key.", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// Valid status values.
+var states map[string]bool = func() map[string]bool {
+	m := map[string]bool{}
+	for s := printorderstatus.STATE_EXPORTED; s <= printorderstatus.STATE_RESEND; s++ {
+		m[s.AsString()] = true
+	}
+	return m
+}()
+
+// Stores a posted status.
+func (s *Server) postStatus(w http.ResponseWriter, r *http.Request) {
+	st := &printorderstatus.Status{}
+	if err := json.NewDecoder(r.Body).Decode(st); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON.")
+		return
+	}
+
+	var problems []string
+	if s.printOrder(st.PrintOrderId) == nil {
+		problems = append(problems, fmt.Sprintf(`The selected print_order_id "%d" is invalid.`, st.PrintOrderId))
+	}
+	if !states[st.Status] {
+		problems = append(problems, fmt.Sprintf(`The selected status "%s" is invalid.`, st.Status))
+	}
+	if len(problems) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, problems...)
+		return
+	}
+
+	st.ID = s.assignID(PATH_STATUSES, 0)
+	s.statuses = append(s.statuses, st)
+	s.postedStatuses = append(s.postedStatuses, st)
+
+	c := *st
+	writeJSON(w, http.StatusOK, []*printorderstatus.Status{&c})
+}
+
+// Stores a posted delivery number.
+func (s *Server) postDeliveryNumber(w http.ResponseWriter, r *http.Request) {
+	d := &deliverynumber.DeliveryNumber{}
+	if err := json.NewDecoder(r.Body).Decode(d); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON.")
+		return
+	}
+
+	if problems := s.validateDeliveryNumber(d); len(problems) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, problems...)
+		return
+	}
+
+	d.ID = s.assignID(PATH_DELIVERY_NUMBERS, 0)
+	s.deliveryNumbers = append(s.deliveryNumbers, d)
+	s.postedDeliveryNumbers = append(s.postedDeliveryNumbers, d)
+
+	c := *d
+	writeJSON(w, http.StatusOK, []*deliverynumber.DeliveryNumber{&c})
+}
+
+// Updates a delivery number.
+func (s *Server) putDeliveryNumber(w http.ResponseWriter, r *http.Request, id int) {
+	k := s.deliveryNumberIndex(id)
+	if k < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf(`No item with id: "%d".`, id))
+		return
+	}
+
+	d := &deliverynumber.DeliveryNumber{}
+	if err := json.NewDecoder(r.Body).Decode(d); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON.")
+		return
+	}
+
+	if problems := s.validateDeliveryNumber(d); len(problems) > 0 {
+		writeError(w, http.StatusUnprocessableEntity, problems...)
+		return
+	}
+
+	d.ID = id
+	d.CreatedAt = s.deliveryNumbers[k].CreatedAt
+	s.deliveryNumbers[k] = d
+
+	c := *d
+	writeJSON(w, http.StatusOK, &c)
+}
+
+// Deletes a delivery number.
+func (s *Server) deleteDeliveryNumber(w http.ResponseWriter, id int) {
+	k := s.deliveryNumberIndex(id)
+	if k < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf(`No item with id: "%d".`, id))
+		return
+	}
+
+	d := s.deliveryNumbers[k]
+	s.deliveryNumbers = append(s.deliveryNumbers[:k:k], s.deliveryNumbers[k+1:]...)
+
+	writeJSON(w, http.StatusOK, d)
+}
+
+// Validates a delivery number.
+func (s *Server) validateDeliveryNumber(d *deliverynumber.DeliveryNumber) []string {
+	var problems []string
+	if s.printOrder(d.PrintOrderID) == nil {
+		problems = append(problems, fmt.Sprintf(`The selected print_order_id "%d" is invalid.`, d.PrintOrderID))
+	}
+	if d.DeliveryNumber == "" {
+		problems = append(problems, "The delivery_number field is required.")
+	}
+	return problems
+}
+
+// Returns the index of delivery number id, or -1.
+func (s *Server) deliveryNumberIndex(id int) int {
+	for k, v := range s.deliveryNumbers {
+		if v.ID == id {
+			return k
+		}
+	}
+	return -1
+}
+
+// Returns print order id, or nil.
+func (s *Server) printOrder(id int) *printorder.PrintOrder {
+	for _, v := range s.printOrders {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// Returns country id, or nil.
+func (s *Server) country(id int) *country.Country {
+	for _, v := range s.countries {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// Returns statuses of print order printOrderID.
+func (s *Server) statusesOf(printOrderID int) printorderstatus.StatusList {
+	var l printorderstatus.StatusList
+	for _, v := range s.statuses {
+		if v.PrintOrderId == printOrderID {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// Returns the JSON names of the attributes of the struct v points to. Relations are left out.
+func attributeNames(v interface{}) []string {
+	t := reflect.TypeOf(v).Elem()
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		kind := f.Type.Kind()
+		if kind == reflect.Ptr || kind == reflect.Slice {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Writes v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Writes a Publit style error payload.
+func writeError(w http.ResponseWriter, status int, infos ...string) {
+	e := ErrorResponse{
+		Code:         status,
+		Type:         strings.ReplaceAll(http.StatusText(status), " ", ""),
+		CombinedInfo: strings.Join(infos, " "),
+	}
+	for _, v := range infos {
+		e.Errors = append(e.Errors, ErrorInfo{Info: v, Type: e.Type})
+	}
+	writeJSON(w, status, e)
+}
@@ -0,0 +1,254 @@
+package productiontest
+
+import (
+	"context"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/ProductionAPIGoSDK/country"
+	"github.com/publitsweden/ProductionAPIGoSDK/deliverynumber"
+	"github.com/publitsweden/ProductionAPIGoSDK/file"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorder"
+	"github.com/publitsweden/ProductionAPIGoSDK/printorderstatus"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCanShowPrintOrderWithRelations(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+
+	s.AddPrintOrders(&printorder.PrintOrder{
+		ClientRef:       "ref",
+		Statuses:        printorderstatus.StatusList{printorderstatus.New(printorderstatus.STATE_EXPORTED, 0, "")},
+		DeliveryCountry: &country.Country{ID: 205, ISO2: "SE"},
+	})
+
+	po, err := printorder.Show(s.Client(), 1, common.QueryWith(printorder.WITH_STATUSES, printorder.WITH_DELIVERY_COUNTRY))
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if po.ClientRef != "ref" {
+		t.Errorf(`Expected client reference "ref" but got: "%s"`, po.ClientRef)
+	}
+
+	if len(po.Statuses) != 1 || po.Statuses[0].Status != printorderstatus.STATE_EXPORTED.AsString() {
+		t.Errorf(`Expected seeded status to be loaded but got: "%v"`, po.Statuses)
+	}
+
+	if po.DeliveryCountry == nil || po.DeliveryCountry.ISO2 != "SE" {
+		t.Errorf(`Expected delivery country to be loaded but got: "%v"`, po.DeliveryCountry)
+	}
+}
+
+func TestCanIndexPrintOrdersWithQuery(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+
+	for _, v := range []string{"a", "b", "a", "a"} {
+		s.AddPrintOrders(&printorder.PrintOrder{ClientRef: v})
+	}
+
+	params, err := printorder.Query().ClientRef("a").OrderByDesc(printorder.ID).Limit(2).Build()
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	resp, err := printorder.Index(s.Client(), params...)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if resp.Count != 3 || len(resp.Data) != 2 || resp.Next == "" {
+		t.Fatalf("Expected first page of 2 out of 3 print orders with a next link, got %d of %d.", len(resp.Data), resp.Count)
+	}
+
+	if resp.Data[0].ID != 4 || resp.Data[1].ID != 3 {
+		t.Errorf("Expected print orders 4 and 3 but got %d and %d.", resp.Data[0].ID, resp.Data[1].ID)
+	}
+
+	all, err := printorder.IndexAll(s.Client(), 0, params...)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if len(all) != 3 {
+		t.Errorf("Expected all 3 print orders when paginating but got %d.", len(all))
+	}
+}
+
+func TestInvalidQueryReturnsErrorPayload(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+
+	_, err := country.Index(s.Client(), common.QueryWith("unknown"))
+
+	re, ok := err.(*production.ResponseError)
+	if !ok || re.StatusCode != http.StatusBadRequest {
+		t.Fatalf(`Expected bad request error but got: "%v"`, err)
+	}
+
+	if re.APIError == nil {
+		t.Error("Expected Publit error payload to be decoded.")
+	}
+}
+
+func TestPostedStatusesAreStored(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	s.AddPrintOrders(&printorder.PrintOrder{})
+
+	st := printorderstatus.New(printorderstatus.STATE_ACCEPTED, 1, "Accepted by printer")
+	if err := st.Store(s.Client()); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if st.ID == 0 {
+		t.Error("Expected stored status to be given an ID.")
+	}
+
+	posted := s.PostedStatuses()
+	if len(posted) != 1 || posted[0].Message != "Accepted by printer" {
+		t.Errorf(`Expected posted status to be recorded but got: "%v"`, posted)
+	}
+
+	err := printorderstatus.New(printorderstatus.STATE_ACCEPTED, 99, "").Store(s.Client())
+	if !production.IsValidation(err) {
+		t.Errorf(`Expected validation error for unknown print order but got: "%v"`, err)
+	}
+}
+
+func TestUnprocessableEntityErrorKeepsMessage(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	s.AddPrintOrders(&printorder.PrintOrder{})
+
+	// Errors are made by production.MakeResponseError.
+	err := deliverynumber.New(1, "", "").Store(s.Client())
+
+	re, ok := err.(*production.ResponseError)
+	if !ok || re.StatusCode != http.StatusUnprocessableEntity || re.APIError == nil {
+		t.Fatalf(`Expected unprocessable entity error with Publit error payload but got: "%v"`, err)
+	}
+
+	expected := "The delivery_number field is required."
+	if re.APIError.CombinedInfo != expected || !strings.Contains(re.Error(), expected) {
+		t.Errorf(`Expected error message "%s" but got: "%s"`, expected, re.Error())
+	}
+}
+
+func TestDeliveryNumbersCanBeStoredUpdatedAndDeleted(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	s.AddPrintOrders(&printorder.PrintOrder{})
+	c := s.Client()
+
+	d := deliverynumber.New(1, "123", "")
+	if err := d.Store(c); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	d.DeliveryNumber = "456"
+	if err := d.Update(c); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if l := s.DeliveryNumbers(1); len(l) != 1 || l[0].DeliveryNumber != "456" {
+		t.Errorf(`Expected updated delivery number but got: "%v"`, l)
+	}
+
+	if err := d.Delete(c); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if l := s.DeliveryNumbers(1); len(l) != 0 {
+		t.Errorf(`Expected delivery number to be deleted but got: "%v"`, l)
+	}
+
+	if l := s.PostedDeliveryNumbers(); len(l) != 1 || l[0].DeliveryNumber != "123" {
+		t.Errorf(`Expected posted delivery number to be recorded but got: "%v"`, l)
+	}
+}
+
+func TestFilesCanBeDownloadedThroughPresignedURLs(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	id := s.AddFile(&file.File{OriginalName: "book.pdf"}, []byte("content"))
+
+	outdir, err := ioutil.TempDir("", "outputdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outdir)
+
+	errs, err := file.FileList{&file.File{ID: id, OriginalName: "book.pdf"}}.DownloadFiles(s.Client(), outdir)
+	if err != nil || errs[id] != nil {
+		t.Fatalf(`Expected download to succeed but got: "%v", "%v"`, err, errs[id])
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(outdir, "book.pdf"))
+	if err != nil || string(b) != "content" {
+		t.Errorf(`Expected downloaded content but got: "%s", "%v"`, b, err)
+	}
+}
+
+func TestFailNextReturnsErrors(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	s.AddCountries(&country.Country{ISO2: "SE"})
+
+	c := s.Client()
+	c.Retry = &production.RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusServiceUnavailable}}
+	s.FailNext(http.StatusServiceUnavailable)
+
+	if _, err := country.Show(c, 1); err != nil {
+		t.Error("Expected call to pass after retry but got error.", err)
+	}
+
+	_, err := country.Show(c, 2)
+	if !production.IsNotFound(err) {
+		t.Errorf(`Expected not found error but got: "%v"`, err)
+	}
+}
+
+func TestStoredStatusesAreOnlyRetriedWhenAllowed(t *testing.T) {
+	t.Parallel()
+	s := NewServer()
+	defer s.Close()
+	s.AddPrintOrders(&printorder.PrintOrder{ID: 1})
+
+	c := s.Client()
+	c.Retry = &production.RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusBadGateway}}
+	s.FailNext(http.StatusBadGateway)
+
+	if err := printorderstatus.New(printorderstatus.STATE_ACCEPTED, 1, "").Store(c); err == nil {
+		t.Error("Expected an error since POST should not be retried but did not receive one.")
+	}
+
+	s.FailNext(http.StatusBadGateway)
+	if err := printorderstatus.New(printorderstatus.STATE_ACCEPTED, 1, "").StoreWithRetry(c); err != nil {
+		t.Error("Expected post to pass after retry but got error.", err)
+	}
+
+	s.FailNext(http.StatusBadGateway)
+	ctx := production.AllowRetry(context.Background())
+	if err := printorderstatus.New(printorderstatus.STATE_IN_PRODUCTION, 1, "").StoreContext(ctx, production.PosterWithContext(c)); err != nil {
+		t.Error("Expected post to pass after retry but got error.", err)
+	}
+
+	if l := s.PostedStatuses(); len(l) != 2 {
+		t.Errorf("Expected 2 posted statuses but got %d.", len(l))
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+// Exposes Publit production API SDK metrics to Prometheus.
+//
+// Metrics is a production.Metrics and a prometheus.Collector. Set it on the production.APIClient and register it:
+//
+//	m := prommetrics.New("myapp")
+//	prometheus.MustRegister(m)
+//
+//	c := production.APIClient{
+//		Client:  client.New(...),
+//		BaseUrl: "https://url.to.publit",
+//		Metrics: m,
+//	}
+package prommetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// Subsystem of all metrics.
+const SUBSYSTEM = "publit_production"
+
+// Result label values.
+const (
+	RESULT_SUCCESS = "success"
+	RESULT_FAILURE = "failure"
+)
+
+// Metrics implements production.Metrics using Prometheus metrics.
+type Metrics struct {
+	requests         *prometheus.CounterVec
+	latency          *prometheus.HistogramVec
+	retries          *prometheus.CounterVec
+	tokenRefreshes   *prometheus.CounterVec
+	presignedFetches *prometheus.CounterVec
+	downloadBytes    prometheus.Counter
+	downloadFailures prometheus.Counter
+}
+
+// Creates new Metrics with metric names prefixed by namespace, e.g. "myapp_publit_production_requests_total".
+// Namespace may be empty.
+func New(namespace string) *Metrics {
+	return &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "requests_total",
+			Help:      "Number of calls to the Publit production API by endpoint, method and status. Status is 0 if no response was received.",
+		}, []string{"endpoint", "method", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of calls to the Publit production API, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "method"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "retries_total",
+			Help:      "Number of retried attempts of calls to the Publit production API.",
+		}, []string{"endpoint", "method"}),
+		tokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "token_refreshes_total",
+			Help:      "Number of API token refreshes by result.",
+		}, []string{"result"}),
+		presignedFetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "presigned_fetches_total",
+			Help:      "Number of presigned URL fetches by result.",
+		}, []string{"result"}),
+		downloadBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "download_bytes_total",
+			Help:      "Number of bytes of downloaded files.",
+		}),
+		downloadFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: SUBSYSTEM,
+			Name:      "download_failures_total",
+			Help:      "Number of failed file downloads.",
+		}),
+	}
+}
+
+// ObserveRequest method to fulfil the production.Metrics interface.
+func (m *Metrics) ObserveRequest(method string, endpoint string, status int, latency time.Duration) {
+	m.requests.WithLabelValues(endpoint, method, strconv.Itoa(status)).Inc()
+	m.latency.WithLabelValues(endpoint, method).Observe(latency.Seconds())
+}
+
+// IncRetry method to fulfil the production.Metrics interface.
+func (m *Metrics) IncRetry(method string, endpoint string) {
+	m.retries.WithLabelValues(endpoint, method).Inc()
+}
+
+// IncTokenRefresh method to fulfil the production.Metrics interface.
+func (m *Metrics) IncTokenRefresh(err error) {
+	m.tokenRefreshes.WithLabelValues(result(err)).Inc()
+}
+
+// IncPresignedFetch method to fulfil the production.Metrics interface.
+func (m *Metrics) IncPresignedFetch(err error) {
+	m.presignedFetches.WithLabelValues(result(err)).Inc()
+}
+
+// AddDownloadBytes method to fulfil the production.Metrics interface.
+func (m *Metrics) AddDownloadBytes(n int64) {
+	m.downloadBytes.Add(float64(n))
+}
+
+// IncDownloadFailure method to fulfil the production.Metrics interface.
+func (m *Metrics) IncDownloadFailure() {
+	m.downloadFailures.Inc()
+}
+
+// Describe method to fulfil the prometheus.Collector interface.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, v := range m.collectors() {
+		v.Describe(ch)
+	}
+}
+
+// Collect method to fulfil the prometheus.Collector interface.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, v := range m.collectors() {
+		v.Collect(ch)
+	}
+}
+
+// Returns all collectors.
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.requests,
+		m.latency,
+		m.retries,
+		m.tokenRefreshes,
+		m.presignedFetches,
+		m.downloadBytes,
+		m.downloadFailures,
+	}
+}
+
+// Returns result label value for err.
+func result(err error) string {
+	if err != nil {
+		return RESULT_FAILURE
+	}
+	return RESULT_SUCCESS
+}
@@ -0,0 +1,55 @@
+package prommetrics
+
+import (
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/publitsweden/ProductionAPIGoSDK"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Compile time check of the production.Metrics interface.
+var _ production.Metrics = &Metrics{}
+
+func TestMetricsAreCollected(t *testing.T) {
+	t.Parallel()
+	m := New("test")
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(m); err != nil {
+		t.Fatal("Could not register metrics.", err)
+	}
+
+	m.ObserveRequest(http.MethodGet, "print_orders/{id}", http.StatusOK, 100*time.Millisecond)
+	m.IncRetry(http.MethodGet, "print_orders/{id}")
+	m.IncTokenRefresh(nil)
+	m.IncPresignedFetch(errors.New("Some error"))
+	m.AddDownloadBytes(1024)
+	m.IncDownloadFailure()
+
+	expected := `
+# HELP test_publit_production_requests_total Number of calls to the Publit production API by endpoint, method and status. Status is 0 if no response was received.
+# TYPE test_publit_production_requests_total counter
+test_publit_production_requests_total{endpoint="print_orders/{id}",method="GET",status="200"} 1
+# HELP test_publit_production_presigned_fetches_total Number of presigned URL fetches by result.
+# TYPE test_publit_production_presigned_fetches_total counter
+test_publit_production_presigned_fetches_total{result="failure"} 1
+# HELP test_publit_production_download_bytes_total Number of bytes of downloaded files.
+# TYPE test_publit_production_download_bytes_total counter
+test_publit_production_download_bytes_total 1024
+`
+	names := []string{
+		"test_publit_production_requests_total",
+		"test_publit_production_presigned_fetches_total",
+		"test_publit_production_download_bytes_total",
+	}
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), names...); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(m); n != 7 {
+		t.Errorf("Expected 7 collected metrics but got %d.", n)
+	}
+}
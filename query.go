@@ -0,0 +1,219 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"errors"
+	"fmt"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Time format used by the Publit APIs.
+const PUBLIT_TIME_FORMAT = "2006-01-02 15:04:05"
+
+// Operator type for attribute filters.
+type Operator string
+
+// Attribute filter operator constants, as defined by common.
+const (
+	OPERATOR_EQUAL         Operator = common.OPERATOR_EQUAL
+	OPERATOR_NOT_EQUAL     Operator = common.OPERATOR_NOT_EQUAL
+	OPERATOR_GREATER       Operator = common.OPERATOR_GREATER
+	OPERATOR_GREATER_EQUAL Operator = common.OPERATOR_GREATER_EQUAL
+	OPERATOR_LESS          Operator = common.OPERATOR_LESS
+	OPERATOR_LESS_EQUAL    Operator = common.OPERATOR_LESS_EQUAL
+	OPERATOR_LIKE          Operator = common.OPERATOR_LIKE
+)
+
+// Attribute filter combinator constants, as defined by common.
+const (
+	COMBINATOR_AND = common.COMBINATOR_AND
+	COMBINATOR_OR  = common.COMBINATOR_OR
+)
+
+// Order direction constants, as defined by common.
+const (
+	ORDER_DIR_ASC  = common.ORDER_DIR_ASC
+	ORDER_DIR_DESC = common.ORDER_DIR_DESC
+)
+
+// Valid operators.
+var operators map[Operator]bool = map[Operator]bool{
+	OPERATOR_EQUAL:         true,
+	OPERATOR_NOT_EQUAL:     true,
+	OPERATOR_GREATER:       true,
+	OPERATOR_GREATER_EQUAL: true,
+	OPERATOR_LESS:          true,
+	OPERATOR_LESS_EQUAL:    true,
+	OPERATOR_LIKE:          true,
+}
+
+// QueryBuilder builds query parameter functions for Index calls, validating attributes, relations and operators.
+// B is the type returned by the fluent methods, which lets resource packages extend the builder with typed methods.
+// Errors are collected and returned by Build.
+type QueryBuilder[B any] struct {
+	self       B
+	attributes map[string]bool
+	relations  map[string]bool
+	params     []func(q url.Values)
+	with       []string
+	errs       []error
+	limit      int
+	offset     int
+	hasLimit   bool
+}
+
+// Creates new QueryBuilder accepting the given attributes and relations.
+// Self is returned from the fluent methods.
+func NewQueryBuilder[B any](self B, attributes []string, relations []string) *QueryBuilder[B] {
+	b := &QueryBuilder[B]{
+		self:       self,
+		attributes: make(map[string]bool, len(attributes)),
+		relations:  make(map[string]bool, len(relations)),
+	}
+
+	for _, v := range attributes {
+		b.attributes[v] = true
+	}
+
+	for _, v := range relations {
+		b.relations[v] = true
+	}
+
+	return b
+}
+
+// Adds an attribute filter combined with previous filters using "and".
+func (b *QueryBuilder[B]) Where(attribute string, op Operator, value string) B {
+	return b.where(attribute, op, value, COMBINATOR_AND)
+}
+
+// Adds an attribute filter combined with previous filters using "or".
+func (b *QueryBuilder[B]) OrWhere(attribute string, op Operator, value string) B {
+	return b.where(attribute, op, value, COMBINATOR_OR)
+}
+
+// Adds an attribute filter on a time attribute. The time is formatted using PUBLIT_TIME_FORMAT.
+func (b *QueryBuilder[B]) WhereTime(attribute string, op Operator, t time.Time) B {
+	return b.Where(attribute, op, t.Format(PUBLIT_TIME_FORMAT))
+}
+
+// Adds relations to load with the response.
+func (b *QueryBuilder[B]) With(relations ...string) B {
+	for _, v := range relations {
+		if !b.relations[v] {
+			b.errs = append(b.errs, fmt.Errorf(`Unknown relation: "%s".`, v))
+			continue
+		}
+		b.with = append(b.with, v)
+	}
+	return b.self
+}
+
+// Orders the response by attribute in direction dir.
+func (b *QueryBuilder[B]) OrderBy(attribute string, dir string) B {
+	if !b.attributes[attribute] {
+		b.errs = append(b.errs, fmt.Errorf(`Unknown attribute to order by: "%s".`, attribute))
+		return b.self
+	}
+
+	if dir != ORDER_DIR_ASC && dir != ORDER_DIR_DESC {
+		b.errs = append(b.errs, fmt.Errorf(`Unknown order direction: "%s".`, dir))
+		return b.self
+	}
+
+	b.params = append(b.params, common.QueryOrderBy([]string{attribute}, dir))
+	return b.self
+}
+
+// Orders the response by attribute in ascending direction.
+func (b *QueryBuilder[B]) OrderByAsc(attribute string) B {
+	return b.OrderBy(attribute, ORDER_DIR_ASC)
+}
+
+// Orders the response by attribute in descending direction.
+func (b *QueryBuilder[B]) OrderByDesc(attribute string) B {
+	return b.OrderBy(attribute, ORDER_DIR_DESC)
+}
+
+// Limits the number of items in the response.
+func (b *QueryBuilder[B]) Limit(limit int) B {
+	if limit <= 0 {
+		b.errs = append(b.errs, fmt.Errorf(`Limit must be positive, got: "%d".`, limit))
+		return b.self
+	}
+	b.limit = limit
+	b.hasLimit = true
+	return b.self
+}
+
+// Skips the first offset items of the response. Requires Limit to be set.
+func (b *QueryBuilder[B]) Offset(offset int) B {
+	if offset < 0 {
+		b.errs = append(b.errs, fmt.Errorf(`Offset can not be negative, got: "%d".`, offset))
+		return b.self
+	}
+	b.offset = offset
+	return b.self
+}
+
+// Adds an error to the builder. Used by typed methods to reject invalid values.
+func (b *QueryBuilder[B]) AddError(err error) B {
+	b.errs = append(b.errs, err)
+	return b.self
+}
+
+// Returns the query parameter functions to pass to Index, or an error if anything invalid was added to the builder.
+func (b *QueryBuilder[B]) Build() ([]func(q url.Values), error) {
+	// Errors found when building are not kept, so that Build can be called again.
+	errs := append([]error{}, b.errs...)
+	if b.offset > 0 && !b.hasLimit {
+		errs = append(errs, errors.New("Offset requires limit to be set."))
+	}
+
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for k, v := range errs {
+			msgs[k] = v.Error()
+		}
+		return nil, errors.New("Invalid query: " + strings.Join(msgs, " "))
+	}
+
+	params := append([]func(q url.Values){}, b.params...)
+
+	if len(b.with) > 0 {
+		params = append(params, common.QueryWith(b.with...))
+	}
+
+	if b.hasLimit {
+		params = append(params, common.QueryLimit(b.limit, b.offset))
+	}
+
+	return params, nil
+}
+
+// Adds an attribute filter with combinator.
+func (b *QueryBuilder[B]) where(attribute string, op Operator, value string, combinator string) B {
+	if !b.attributes[attribute] {
+		b.errs = append(b.errs, fmt.Errorf(`Unknown attribute: "%s".`, attribute))
+		return b.self
+	}
+
+	if !operators[op] {
+		b.errs = append(b.errs, fmt.Errorf(`Unknown operator: "%s".`, op))
+		return b.self
+	}
+
+	b.params = append(b.params, common.QueryAttr(common.AttrQuery{
+		Name:  attribute,
+		Value: value,
+		Args: common.AttrArgs{
+			Operator:   string(op),
+			Combinator: combinator,
+		},
+	}))
+	return b.self
+}
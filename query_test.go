@@ -0,0 +1,98 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"github.com/publitsweden/APIUtilityGoSDK/common"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryBuilderBuildsParams(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	params, err := newTestQuery().
+		WhereTime("created_at", OPERATOR_GREATER_EQUAL, created).
+		With("statuses").
+		OrderByDesc("id").
+		Limit(50).
+		Build()
+
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	expected := applyParams(
+		common.QueryAttr(common.AttrQuery{
+			Name:  "created_at",
+			Value: "2017-01-01 00:00:00",
+			Args: common.AttrArgs{
+				Operator:   string(OPERATOR_GREATER_EQUAL),
+				Combinator: COMBINATOR_AND,
+			},
+		}),
+		common.QueryOrderBy([]string{"id"}, ORDER_DIR_DESC),
+		common.QueryWith("statuses"),
+		common.QueryLimit(50, 0),
+	)
+
+	if got := applyParams(params...); !reflect.DeepEqual(got, expected) {
+		t.Errorf(`Built query did not match expected. Got: "%v", expected: "%v"`, got.Encode(), expected.Encode())
+	}
+}
+
+func TestQueryBuilderRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+	table := []struct {
+		TestName string
+		Query    *testQuery
+	}{
+		{"Unknown attribute", newTestQuery().Where("unknown", OPERATOR_EQUAL, "1")},
+		{"Unknown operator", newTestQuery().Where("id", Operator("~"), "1")},
+		{"Unknown relation", newTestQuery().With("unknown")},
+		{"Unknown order attribute", newTestQuery().OrderByAsc("unknown")},
+		{"Unknown order direction", newTestQuery().OrderBy("id", "sideways")},
+		{"Non positive limit", newTestQuery().Limit(0)},
+		{"Offset without limit", newTestQuery().Offset(10)},
+	}
+
+	for _, v := range table {
+		t.Run(v.TestName, func(t *testing.T) {
+			params, err := v.Query.Build()
+			if err == nil {
+				t.Error("Did not receive an error but was expecting one.")
+			}
+			if params != nil {
+				t.Error("Expected no params to be returned for invalid query.")
+			}
+
+			// Building again gives the same error.
+			if _, again := v.Query.Build(); err != nil && (again == nil || again.Error() != err.Error()) {
+				t.Errorf(`Expected the same error when building again but got: "%v"`, again)
+			}
+		})
+	}
+}
+
+// Query builder for tests.
+type testQuery struct {
+	*QueryBuilder[*testQuery]
+}
+
+// Creates new test query builder.
+func newTestQuery() *testQuery {
+	q := &testQuery{}
+	q.QueryBuilder = NewQueryBuilder(q, []string{"id", "created_at"}, []string{"statuses"})
+	return q
+}
+
+// Applies query param functions to new url.Values.
+func applyParams(params ...func(q url.Values)) url.Values {
+	q := url.Values{}
+	for _, v := range params {
+		v(q)
+	}
+	return q
+}
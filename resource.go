@@ -0,0 +1,85 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// IndexResponse is the response of indexing a resource in the Publit production API.
+// L is the list type holding the items of the response.
+type IndexResponse[L any] struct {
+	Count int    `json:"count"`
+	Next  string `json:"next"`
+	Prev  string `json:"prev"`
+	Data  L      `json:"data"`
+}
+
+// ResourceEndpoint fulfils the Endpointer interface for a resource collection, or a single item in it if Id is set.
+type ResourceEndpoint struct {
+	Path string
+	Id   int
+}
+
+// Method to ResourceEndpoint that fullfils the Endpointer interface.
+func (e ResourceEndpoint) GetEndpoint() string {
+	if e.Id != 0 {
+		return fmt.Sprintf("%v/%v", e.Path, e.Id)
+	}
+	return e.Path
+}
+
+// Resource handles calls against a resource in the Publit production API.
+// T is the type of a single item and I the type of the index response, usually based on IndexResponse.
+//
+// Adding a new resource only requires declaring its types and path:
+//
+//	type IndexResponse production.IndexResponse[[]Thing]
+//	var resource = production.NewResource[Thing, IndexResponse]("things")
+type Resource[T any, I any] struct {
+	// Path of the resource collection, relative to the API version. E.g. "print_orders".
+	Path string
+}
+
+// Creates new Resource for path.
+func NewResource[T any, I any](path string) Resource[T, I] {
+	return Resource[T, I]{Path: path}
+}
+
+// Returns endpoint of the resource collection, or of a single item if id is not zero.
+func (r Resource[T, I]) Endpoint(id int) ResourceEndpoint {
+	return ResourceEndpoint{Path: r.Path, Id: id}
+}
+
+// Returns item with id from the Publit API.
+func (r Resource[T, I]) Show(ctx context.Context, c ContextGetter, id int, queryParams ...func(q url.Values)) (*T, error) {
+	m := new(T)
+	err := c.GetContext(ctx, r.Endpoint(id), m, queryParams...)
+	return m, err
+}
+
+// Indexes items from the Publit API.
+func (r Resource[T, I]) Index(ctx context.Context, c ContextGetter, queryParams ...func(q url.Values)) (*I, error) {
+	ir := new(I)
+	err := c.GetContext(ctx, r.Endpoint(0), ir, queryParams...)
+	return ir, err
+}
+
+// Creates item in the Publit API.
+// The API responds with a list of the created items, item is updated with the first one.
+func (r Resource[T, I]) Create(ctx context.Context, c ContextPoster, item *T) error {
+	result := []*T{item}
+	return c.PostContext(ctx, r.Endpoint(0), item, &result)
+}
+
+// Updates item with id in the Publit API. Item is updated with the response.
+func (r Resource[T, I]) Update(ctx context.Context, c ContextPutter, id int, item *T) error {
+	return c.PutContext(ctx, r.Endpoint(id), item, item)
+}
+
+// Deletes item with id from the Publit API. The response is decoded into result.
+func (r Resource[T, I]) Delete(ctx context.Context, c ContextDeleter, id int, result *T) error {
+	return c.DeleteContext(ctx, r.Endpoint(id), result)
+}
@@ -0,0 +1,122 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"net/http"
+	"testing"
+)
+
+// Test resource item.
+type Thing struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Test resource index response.
+type ThingIndexResponse IndexResponse[[]Thing]
+
+func TestResourceEndpoint(t *testing.T) {
+	t.Parallel()
+	r := NewResource[Thing, ThingIndexResponse]("things")
+
+	if e := r.Endpoint(0).GetEndpoint(); e != "things" {
+		t.Errorf(`Collection endpoint did not match expected. Got: "%s"`, e)
+	}
+
+	if e := r.Endpoint(5).GetEndpoint(); e != "things/5" {
+		t.Errorf(`Item endpoint did not match expected. Got: "%s"`, e)
+	}
+}
+
+func TestResourceCanShowAndIndex(t *testing.T) {
+	t.Parallel()
+	r := NewResource[Thing, ThingIndexResponse]("things")
+
+	caller := &MockAPICaller{}
+	caller.T = t
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	caller.CallTestCallback = func(t *testing.T, req *http.Request) {
+		if req.URL.Path != "somebaseurl/production/v2.0/things/5" {
+			t.Errorf(`Show URL did not match expected. Got: "%s"`, req.URL.Path)
+		}
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `{"id":5,"name":"thing"}`)
+
+	thing, err := r.Show(context.Background(), c, 5)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if thing.ID != 5 || thing.Name != "thing" {
+		t.Errorf(`Show response did not match expected. Got: "%+v"`, thing)
+	}
+
+	caller.CallTestCallback = func(t *testing.T, req *http.Request) {
+		if req.URL.Path != "somebaseurl/production/v2.0/things" {
+			t.Errorf(`Index URL did not match expected. Got: "%s"`, req.URL.Path)
+		}
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `{"count":2,"data":[{"id":1},{"id":2}]}`)
+
+	ir, err := r.Index(context.Background(), c)
+	if err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if ir.Count != 2 || len(ir.Data) != 2 {
+		t.Errorf(`Index response did not match expected. Got: "%+v"`, ir)
+	}
+}
+
+func TestResourceCanCreateUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+	r := NewResource[Thing, ThingIndexResponse]("things")
+
+	caller := &MockAPICaller{}
+	caller.T = t
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	thing := &Thing{Name: "new"}
+	caller.CallTestCallback = func(t *testing.T, req *http.Request) {
+		if req.Method != http.MethodPost || req.URL.Path != "somebaseurl/production/v2.0/things" {
+			t.Errorf(`Create request did not match expected. Got: "%s %s"`, req.Method, req.URL.Path)
+		}
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `[{"id":7,"name":"new"}]`)
+
+	if err := r.Create(context.Background(), c, thing); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if thing.ID != 7 {
+		t.Errorf(`Created item was not updated with response. Got: "%+v"`, thing)
+	}
+
+	caller.CallTestCallback = func(t *testing.T, req *http.Request) {
+		if req.Method != http.MethodPut || req.URL.Path != "somebaseurl/production/v2.0/things/7" {
+			t.Errorf(`Update request did not match expected. Got: "%s %s"`, req.Method, req.URL.Path)
+		}
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `{"id":7,"name":"updated"}`)
+
+	if err := r.Update(context.Background(), c, thing.ID, thing); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+
+	if thing.Name != "updated" {
+		t.Errorf(`Updated item was not updated with response. Got: "%+v"`, thing)
+	}
+
+	caller.CallTestCallback = func(t *testing.T, req *http.Request) {
+		if req.Method != http.MethodDelete || req.URL.Path != "somebaseurl/production/v2.0/things/7" {
+			t.Errorf(`Delete request did not match expected. Got: "%s %s"`, req.Method, req.URL.Path)
+		}
+	}
+	caller.Response = createCallerResponse(http.StatusOK, `{"id":7,"name":"updated"}`)
+
+	if err := r.Delete(context.Background(), c, thing.ID, thing); err != nil {
+		t.Fatal("Got error but was not expecting one.", err)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy defines how failed calls against the Publit production API are retried.
+// Only idempotent methods (GET, PUT, DELETE) are retried unless RetryNonIdempotent is set
+// or the request context has been marked with AllowRetry.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one. Values below 2 disables retries.
+	MaxAttempts int
+	// Delay before the first retry. The delay is doubled for each following attempt.
+	BaseDelay time.Duration
+	// Upper limit for the delay between two attempts. Zero means no limit.
+	MaxDelay time.Duration
+	// Fraction (0-1) of the delay that is randomized to spread out retries from concurrent callers.
+	Jitter float64
+	// Response status codes that should be retried.
+	RetryableStatusCodes []int
+	// Decides if an error returned from the APICaller should be retried.
+	// IsRetryableNetworkError is used if not set.
+	RetryableError func(err error) bool
+	// Retry non idempotent methods (POST) as well.
+	RetryNonIdempotent bool
+}
+
+// Returns a RetryPolicy with sensible defaults for the Publit production API.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// Context key for marking requests as safe to retry.
+type allowRetryKey struct{}
+
+// Returns a context that marks requests made with it as safe to retry even if the method is not idempotent.
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowRetryKey{}, true)
+}
+
+// Checks if the context has been marked with AllowRetry.
+func retryAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(allowRetryKey{}).(bool)
+	return allowed
+}
+
+// Checks if err is a transient network error worth retrying.
+// Timeouts, connection resets, refused connections and unexpected EOFs are considered retryable.
+// Context cancellation and deadlines are never retryable.
+func IsRetryableNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// Returns the number of attempts allowed for req.
+func (p *RetryPolicy) attemptsFor(req *http.Request) int {
+	if p == nil || p.MaxAttempts < 2 {
+		return 1
+	}
+
+	if !isIdempotent(req.Method) && !p.RetryNonIdempotent && !retryAllowed(req.Context()) {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// Checks if the outcome of an attempt should be retried.
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		if p.RetryableError != nil {
+			return p.RetryableError(err)
+		}
+		return IsRetryableNetworkError(err)
+	}
+
+	if resp == nil {
+		return false
+	}
+
+	for _, v := range p.RetryableStatusCodes {
+		if resp.StatusCode == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Calculates the delay before the next attempt.
+// The Retry-After header of resp is honored if it asks for a longer delay than the backoff.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	d := time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(attempt-1)))
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d < 0) {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+
+	if ra, ok := retryAfter(resp); ok && ra > d {
+		d = ra
+	}
+
+	return d
+}
+
+// Parses the Retry-After header of resp. Supports both delay in seconds and HTTP dates.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// Checks if method is idempotent according to RFC 7231.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Returns a copy of req with a fresh body, so that it can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	return r, nil
+}
+
+// Waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// Discards and closes the body of a response that will not be handed to the caller.
+func discardResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,246 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestGetIsRetriedOnRetryableStatus(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusServiceUnavailable, `{}`),
+			createCallerResponse(http.StatusOK, `{"some":"body"}`),
+		},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	i := &struct {
+		Some string `json:"some"`
+	}{}
+	err := c.Get(NewEndpoint(), i)
+
+	if err != nil {
+		t.Error("Expected Get to pass after retries but received error.", err.Error())
+	}
+
+	if len(caller.Requests) != 3 {
+		t.Errorf("Expected 3 attempts but got %d.", len(caller.Requests))
+	}
+
+	if i.Some != "body" {
+		t.Error("Unmarshalled struct did not match expected.")
+	}
+}
+
+func TestGetIsRetriedOnNetworkError(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Errors:    []error{syscall.ECONNRESET, nil},
+		Responses: []*http.Response{nil, createCallerResponse(http.StatusOK, `{}`)},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	i := &struct{}{}
+	if err := c.Get(NewEndpoint(), i); err != nil {
+		t.Error("Expected Get to pass after retry but received error.", err.Error())
+	}
+
+	if len(caller.Requests) != 2 {
+		t.Errorf("Expected 2 attempts but got %d.", len(caller.Requests))
+	}
+}
+
+func TestRetriesStopAtMaxAttempts(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusOK, `{}`),
+		},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	i := &struct{}{}
+	if err := c.Get(NewEndpoint(), i); err == nil {
+		t.Error("Expected an error after exhausting attempts but did not receive one.")
+	}
+
+	if len(caller.Requests) != 3 {
+		t.Errorf("Expected 3 attempts but got %d.", len(caller.Requests))
+	}
+}
+
+func TestPostIsNotRetriedByDefault(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusOK, `{}`),
+		},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	i := &struct{}{}
+	if err := c.Post(NewEndpoint(), i, i); err == nil {
+		t.Error("Expected an error since POST should not be retried but did not receive one.")
+	}
+
+	if len(caller.Requests) != 1 {
+		t.Errorf("Expected 1 attempt but got %d.", len(caller.Requests))
+	}
+}
+
+func TestPostIsRetriedWithReplayedBodyWhenAllowed(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusOK, `{"name":"stored"}`),
+		},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	i := &struct {
+		Name string `json:"name"`
+	}{Name: "test"}
+	if err := c.PostContext(AllowRetry(context.Background()), NewEndpoint(), i, i); err != nil {
+		t.Error("Expected POST to pass after retry but received error.", err.Error())
+	}
+
+	if len(caller.Bodies) != 2 {
+		t.Fatalf("Expected 2 attempts but got %d.", len(caller.Bodies))
+	}
+
+	for k, v := range caller.Bodies {
+		if v != `{"name":"test"}` {
+			t.Errorf(`Body of attempt %d did not match expected. Got: "%s"`, k+1, v)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+	resp := createCallerResponse(http.StatusTooManyRequests, `{}`)
+	resp.Header = http.Header{"Retry-After": []string{"1"}}
+
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{resp, createCallerResponse(http.StatusOK, `{}`)},
+	}
+
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: testRetryPolicy()}
+
+	start := time.Now()
+	i := &struct{}{}
+	if err := c.Get(NewEndpoint(), i); err != nil {
+		t.Error("Expected Get to pass after retry but received error.", err.Error())
+	}
+
+	if time.Since(start) < time.Second {
+		t.Error("Retry did not wait for the duration given by Retry-After.")
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	t.Parallel()
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusBadGateway, `{}`),
+			createCallerResponse(http.StatusOK, `{}`),
+		},
+	}
+
+	p := testRetryPolicy()
+	p.BaseDelay = time.Minute
+	p.MaxDelay = time.Minute
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Retry: p}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	i := &struct{}{}
+	err := c.GetContext(ctx, NewEndpoint(), i)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(`Expected context.DeadlineExceeded but got: "%v"`, err)
+	}
+}
+
+func TestIsRetryableNetworkError(t *testing.T) {
+	t.Parallel()
+	table := []struct {
+		Err       error
+		Retryable bool
+	}{
+		{syscall.ECONNRESET, true},
+		{syscall.ECONNREFUSED, true},
+		{context.Canceled, false},
+		{errors.New("Some error"), false},
+		{nil, false},
+	}
+
+	for _, v := range table {
+		if IsRetryableNetworkError(v.Err) != v.Retryable {
+			t.Errorf(`Expected retryable to be %v for "%v".`, v.Retryable, v.Err)
+		}
+	}
+}
+
+// Retry policy without delays to keep tests fast.
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.MaxAttempts = 3
+	p.BaseDelay = time.Millisecond
+	return p
+}
+
+// APICaller mock returning responses and errors in sequence.
+type SequenceAPICaller struct {
+	Responses []*http.Response
+	Errors    []error
+	Requests  []*http.Request
+	Bodies    []string
+}
+
+func (c *SequenceAPICaller) Call(r *http.Request) (*http.Response, error) {
+	n := len(c.Requests)
+	c.Requests = append(c.Requests, r)
+
+	if r.Body != nil {
+		b, _ := ioutil.ReadAll(r.Body)
+		c.Bodies = append(c.Bodies, string(b))
+	}
+
+	var err error
+	if n < len(c.Errors) {
+		err = c.Errors[n]
+	}
+
+	if n >= len(c.Responses) {
+		return nil, errors.New("No more responses")
+	}
+
+	return c.Responses[n], err
+}
+
+func (c *SequenceAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *SequenceAPICaller) SetNewAPIToken(r *http.Request) error {
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// TokenRefresher coordinates the token refreshes of an APIClient, so that concurrent calls hitting unauthorized
+// responses at the same time only refresh the token once. Clients without one coordinate the refreshes of calls
+// through the same APICaller. Share one between clients whose APICallers share a token. The zero value is ready to use.
+type TokenRefresher struct {
+	mu sync.Mutex
+	// Incremented for every successful refresh.
+	generation uint64
+	inflight   *refreshCall
+}
+
+// TokenRefreshers of clients without one, shared by the calls made through the same APICaller while any of them is
+// in progress. Entries are keyed on the address of the APICaller, which the calls keep alive, and are removed when
+// the last call is done.
+var callerRefreshers = &refresherRegistry{entries: map[uintptr]*registeredRefresher{}}
+
+// Registry of TokenRefreshers by APICaller.
+type refresherRegistry struct {
+	mu      sync.Mutex
+	entries map[uintptr]*registeredRefresher
+}
+
+// TokenRefresher in use by calls through an APICaller.
+type registeredRefresher struct {
+	refresher TokenRefresher
+	calls     int
+}
+
+// Returns the TokenRefresher of calls through c and a function releasing it when the call is done.
+// Returns nil for callers that are not pointers, as their calls can not be told apart from calls through copies.
+func (r *refresherRegistry) acquire(c APICaller) (*TokenRefresher, func()) {
+	v := reflect.ValueOf(c)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return nil, func() {}
+	}
+	key := v.Pointer()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[key]
+	if !ok {
+		e = &registeredRefresher{}
+		r.entries[key] = e
+	}
+	e.calls++
+
+	return &e.refresher, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		e.calls--
+		if e.calls == 0 {
+			delete(r.entries, key)
+		}
+	}
+}
+
+// A refresh in progress.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Returns the refresh generation. Requests should record the generation before being sent.
+func (t *TokenRefresher) currentGeneration() uint64 {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.generation
+}
+
+// Refreshes the API token of c using r, unless another refresh has completed since generation seen.
+// Concurrent callers share a single refresh. Refreshed reports if this call performed the refresh.
+func (t *TokenRefresher) refresh(ctx context.Context, c APICaller, seen uint64, r *http.Request) (refreshed bool, err error) {
+	// Without a TokenRefresher every call refreshes on its own.
+	if t == nil {
+		return true, c.SetNewAPIToken(r)
+	}
+
+	t.mu.Lock()
+
+	// The token was refreshed after the request was sent, just replay it.
+	if t.generation != seen {
+		t.mu.Unlock()
+		return false, nil
+	}
+
+	if call := t.inflight; call != nil {
+		t.mu.Unlock()
+		select {
+		case <-call.done:
+			return false, call.err
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	t.inflight = call
+	t.mu.Unlock()
+
+	call.err = c.SetNewAPIToken(r)
+
+	t.mu.Lock()
+	t.inflight = nil
+	if call.err == nil {
+		t.generation++
+	}
+	t.mu.Unlock()
+	close(call.done)
+
+	return true, call.err
+}
+
+// Performs call and, if the response is unauthorized, refreshes the API token and replays the request once.
+func (c APIClient) callWithTokenRefresh(req *http.Request) (*http.Response, error) {
+	if c.DisableTokenRefresh {
+		return c.callWithRetry(req)
+	}
+
+	refresher := c.TokenRefresher
+	if refresher == nil {
+		var release func()
+		refresher, release = callerRefreshers.acquire(c.Client)
+		defer release()
+	}
+
+	gen := refresher.currentGeneration()
+
+	resp, err := c.callWithRetry(req)
+	if err != nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	discardResponse(resp)
+
+	refreshed, err := refresher.refresh(req.Context(), c.Client, gen, req)
+	if refreshed {
+		c.CallMetrics().IncTokenRefresh(err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not refresh API token: %w", ErrUnauthorized, err)
+	}
+
+	r, err := rewindRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.callWithRetry(r)
+}
@@ -0,0 +1,179 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnauthorizedResponseRefreshesTokenAndReplays(t *testing.T) {
+	t.Parallel()
+	caller := &TokenAPICaller{}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	i := &struct {
+		Name string `json:"name"`
+	}{Name: "test"}
+	err := c.Post(NewEndpoint(), i, i)
+
+	if err != nil {
+		t.Error("Expected Post to pass after token refresh but received error.", err)
+	}
+
+	if caller.Refreshes() != 1 {
+		t.Errorf("Expected 1 token refresh but got %d.", caller.Refreshes())
+	}
+
+	for k, v := range caller.Bodies() {
+		if v != `{"name":"test"}` {
+			t.Errorf(`Body of attempt %d did not match expected. Got: "%s"`, k+1, v)
+		}
+	}
+}
+
+func TestUnauthorizedIsReturnedIfReplayFails(t *testing.T) {
+	t.Parallel()
+	caller := &TokenAPICaller{AlwaysUnauthorized: true}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	i := &struct{}{}
+	err := c.Get(NewEndpoint(), i)
+
+	if !IsUnauthorized(err) {
+		t.Errorf(`Expected unauthorized error but got: "%v"`, err)
+	}
+
+	if caller.Calls() != 2 {
+		t.Errorf("Expected request to be replayed once, but got %d calls.", caller.Calls())
+	}
+}
+
+func TestUnauthorizedIsReturnedIfRefreshFails(t *testing.T) {
+	t.Parallel()
+	caller := &TokenAPICaller{RefreshError: errors.New("Some error")}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl"}
+
+	i := &struct{}{}
+	err := c.Get(NewEndpoint(), i)
+
+	if !IsUnauthorized(err) {
+		t.Errorf(`Expected unauthorized error but got: "%v"`, err)
+	}
+}
+
+func TestTokenRefreshCanBeDisabled(t *testing.T) {
+	t.Parallel()
+	caller := &TokenAPICaller{}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", DisableTokenRefresh: true}
+
+	i := &struct{}{}
+	err := c.Get(NewEndpoint(), i)
+
+	if !IsUnauthorized(err) {
+		t.Errorf(`Expected unauthorized error but got: "%v"`, err)
+	}
+
+	if caller.Refreshes() != 0 {
+		t.Errorf("Expected no token refresh but got %d.", caller.Refreshes())
+	}
+}
+
+func TestConcurrentUnauthorizedResponsesRefreshOnce(t *testing.T) {
+	t.Parallel()
+	tests := map[string]*TokenRefresher{
+		"Default":              nil,
+		"Zero value refresher": &TokenRefresher{},
+	}
+
+	for name, refresher := range tests {
+		caller := &TokenAPICaller{RefreshDelay: 20 * time.Millisecond}
+
+		var wg sync.WaitGroup
+		for n := 0; n < 10; n++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c := APIClient{Client: caller, BaseUrl: "somebaseurl", TokenRefresher: refresher}
+				i := &struct{}{}
+				if err := c.Get(NewEndpoint(), i); err != nil {
+					t.Errorf("%s: Expected Get to pass after token refresh but received error. %v", name, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if caller.Refreshes() != 1 {
+			t.Errorf("%s: Expected 1 token refresh but got %d.", name, caller.Refreshes())
+		}
+	}
+}
+
+// APICaller mock responding unauthorized until the token has been refreshed.
+type TokenAPICaller struct {
+	AlwaysUnauthorized bool
+	RefreshError       error
+	RefreshDelay       time.Duration
+
+	mu        sync.Mutex
+	valid     bool
+	refreshes int
+	bodies    []string
+	calls     int32
+}
+
+func (c *TokenAPICaller) Call(r *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.calls, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r.Body != nil {
+		b, _ := ioutil.ReadAll(r.Body)
+		c.bodies = append(c.bodies, string(b))
+	}
+
+	if !c.valid || c.AlwaysUnauthorized {
+		return createCallerResponse(http.StatusUnauthorized, `{}`), nil
+	}
+
+	return createCallerResponse(http.StatusOK, `{}`), nil
+}
+
+func (c *TokenAPICaller) CallRaw(r *http.Request) (*http.Response, error) {
+	return c.Call(r)
+}
+
+func (c *TokenAPICaller) SetNewAPIToken(r *http.Request) error {
+	time.Sleep(c.RefreshDelay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refreshes++
+	if c.RefreshError != nil {
+		return c.RefreshError
+	}
+	c.valid = true
+	return nil
+}
+
+func (c *TokenAPICaller) Refreshes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshes
+}
+
+func (c *TokenAPICaller) Bodies() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bodies
+}
+
+func (c *TokenAPICaller) Calls() int {
+	return int(atomic.LoadInt32(&c.calls))
+}
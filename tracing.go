@@ -0,0 +1,124 @@
+// Copyright 2017 Publit Sweden AB. All rights reserved.
+
+package production
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Span attribute keys set on call spans.
+const (
+	SPAN_ATTR_METHOD      = "http.request.method"
+	SPAN_ATTR_STATUS_CODE = "http.response.status_code"
+	SPAN_ATTR_ENDPOINT    = "publit.endpoint"
+	SPAN_ATTR_RETRY_COUNT = "publit.retry_count"
+	// Type of the Publit error payload, or its code if it has no type.
+	SPAN_ATTR_API_ERROR = "publit.api_error"
+)
+
+// Tracer starts spans for the calls made by an APIClient.
+// The oteltracing package provides an OpenTelemetry implementation, which keeps this package free of dependencies.
+type Tracer interface {
+	// Starts a span named name as a child of any span in ctx. The returned context holds the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Writes the trace context of ctx into h, e.g. as W3C traceparent and tracestate headers.
+	Inject(ctx context.Context, h http.Header)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// Records err on the span and marks it as failed.
+	RecordError(err error)
+	// Marks the span as failed with description, without recording an error.
+	SetErrorStatus(description string)
+	End()
+}
+
+// Tracing is implemented by clients that can provide a Tracer, such as APIClient.
+// Resource packages use it to trace work done outside of API calls, e.g. file downloads.
+type Tracing interface {
+	CallTracer() Tracer
+}
+
+// Returns the Tracer of the client. Nil if calls are not traced.
+func (c APIClient) CallTracer() Tracer {
+	return c.Tracer
+}
+
+// Returns the Tracer of c if it implements Tracing, otherwise nil.
+func TracerOf(c interface{}) Tracer {
+	if t, ok := c.(Tracing); ok {
+		return t.CallTracer()
+	}
+	return nil
+}
+
+// Returns the template of endpoint, with ids replaced by "{id}". E.g. "print_orders/4" gives "print_orders/{id}".
+func EndpointTemplate(endpoint string) string {
+	parts := strings.Split(endpoint, "/")
+	for k, v := range parts {
+		if _, err := strconv.Atoi(v); err == nil {
+			parts[k] = "{id}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Starts a span for a call to endpoint, injecting its trace context into the request headers.
+// Returns the request to make and a function ending the span.
+func (c APIClient) startSpan(endpoint string, req *http.Request) (*http.Request, func(resp *http.Response, err error, info *callInfo)) {
+	if c.Tracer == nil {
+		return req, func(*http.Response, error, *callInfo) {}
+	}
+
+	ctx, span := c.Tracer.Start(req.Context(), EndpointTemplate(endpoint))
+	span.SetAttribute(SPAN_ATTR_METHOD, req.Method)
+	span.SetAttribute(SPAN_ATTR_ENDPOINT, endpoint)
+
+	req = req.WithContext(ctx)
+	h := req.Header.Clone()
+	if h == nil {
+		h = http.Header{}
+	}
+	c.Tracer.Inject(ctx, h)
+	req.Header = h
+
+	return req, func(resp *http.Response, err error, info *callInfo) {
+		defer span.End()
+
+		if info.attempts > 1 {
+			span.SetAttribute(SPAN_ATTR_RETRY_COUNT, info.attempts-1)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			return
+		}
+
+		if resp == nil {
+			return
+		}
+
+		span.SetAttribute(SPAN_ATTR_STATUS_CODE, resp.StatusCode)
+
+		if !successStatus(resp.StatusCode) {
+			re := peekResponseError(resp)
+			if re.APIError == nil {
+				span.RecordError(re)
+				return
+			}
+
+			// The attribute is kept low in cardinality, the message is only set as the status description.
+			apiErr := re.APIError.Type
+			if apiErr == "" {
+				apiErr = strconv.Itoa(re.APIError.Code)
+			}
+			span.SetAttribute(SPAN_ATTR_API_ERROR, apiErr)
+			span.SetErrorStatus(re.Error())
+		}
+	}
+}
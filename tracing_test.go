@@ -0,0 +1,145 @@
+package production_test
+
+import (
+	. "github.com/publitsweden/ProductionAPIGoSDK"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEndpointTemplate(t *testing.T) {
+	t.Parallel()
+	table := map[string]string{
+		"print_orders":                 "print_orders",
+		"print_orders/4":               "print_orders/{id}",
+		"print_orders/4/delivery/1234": "print_orders/{id}/delivery/{id}",
+	}
+
+	for endpoint, expected := range table {
+		if got := EndpointTemplate(endpoint); got != expected {
+			t.Errorf(`Template did not match expected. Got: "%s", expected: "%s"`, got, expected)
+		}
+	}
+}
+
+func TestCallsAreTraced(t *testing.T) {
+	t.Parallel()
+	tracer := &RecordingTracer{}
+	caller := &SequenceAPICaller{
+		Responses: []*http.Response{
+			createCallerResponse(http.StatusServiceUnavailable, `{}`),
+			createCallerResponse(http.StatusNotFound, `{}`),
+		},
+		Errors: []error{nil, nil},
+	}
+	c := &APIClient{
+		Client:  caller,
+		BaseUrl: "somebaseurl",
+		Retry:   &RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusServiceUnavailable}},
+		Tracer:  tracer,
+	}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	if len(tracer.Spans) != 1 {
+		t.Fatalf("Expected 1 span but got %d.", len(tracer.Spans))
+	}
+
+	s := tracer.Spans[0]
+	if s.Name != "someendpoint" {
+		t.Errorf(`Expected span to be named after endpoint but got: "%s"`, s.Name)
+	}
+
+	if s.Attributes[SPAN_ATTR_STATUS_CODE] != http.StatusNotFound {
+		t.Errorf(`Expected status code attribute 404 but got: "%v"`, s.Attributes[SPAN_ATTR_STATUS_CODE])
+	}
+
+	if s.Attributes[SPAN_ATTR_RETRY_COUNT] != 1 {
+		t.Errorf(`Expected retry count attribute 1 but got: "%v"`, s.Attributes[SPAN_ATTR_RETRY_COUNT])
+	}
+
+	if s.Err == nil || !s.Ended {
+		t.Error("Expected span to be ended with an error.")
+	}
+
+	for k, r := range caller.Requests {
+		if r.Header.Get("Traceparent") != "someparent" {
+			t.Errorf("Expected trace context to be injected in attempt %d.", k+1)
+		}
+	}
+}
+
+func TestAPIErrorsAreTracedByType(t *testing.T) {
+	t.Parallel()
+	tracer := &RecordingTracer{}
+	resp := createCallerResponse(http.StatusUnprocessableEntity, `{"Code":422,"Type":"ValidationError","CombinedInfo":"Name Jane Doe is taken."}`)
+	resp.Header = http.Header{"Content-Type": []string{"application/json"}}
+	caller := &SequenceAPICaller{Responses: []*http.Response{resp}, Errors: []error{nil}}
+	c := &APIClient{Client: caller, BaseUrl: "somebaseurl", Tracer: tracer}
+
+	c.Get(NewEndpoint(), &struct{}{})
+
+	s := tracer.Spans[0]
+	if s.Attributes[SPAN_ATTR_API_ERROR] != "ValidationError" {
+		t.Errorf(`Expected API error attribute "ValidationError" but got: "%v"`, s.Attributes[SPAN_ATTR_API_ERROR])
+	}
+
+	if !strings.Contains(s.Status, "Name Jane Doe is taken.") || s.Err != nil {
+		t.Errorf(`Expected message in status description only but got: "%s", "%v"`, s.Status, s.Err)
+	}
+}
+
+// Tracer recording spans.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	Spans []*RecordedSpan
+}
+
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &RecordedSpan{Name: name, Attributes: map[string]interface{}{}}
+	t.Spans = append(t.Spans, s)
+	return ctx, s
+}
+
+func (t *RecordingTracer) Inject(ctx context.Context, h http.Header) {
+	h.Set("Traceparent", "someparent")
+}
+
+// Span recorded by RecordingTracer.
+type RecordedSpan struct {
+	mu         sync.Mutex
+	Name       string
+	Attributes map[string]interface{}
+	Err        error
+	Status     string
+	Ended      bool
+}
+
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+func (s *RecordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err
+}
+
+func (s *RecordedSpan) SetErrorStatus(description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Status = description
+}
+
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Ended = true
+}